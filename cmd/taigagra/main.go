@@ -16,18 +16,26 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+	"unicode"
 
 	"github.com/mymmrac/telego"
 
@@ -37,10 +45,12 @@ import (
 	"github.com/iho/taigagra/internal/config"
 	"github.com/iho/taigagra/internal/storage"
 	"github.com/iho/taigagra/internal/taiga"
+	"github.com/iho/taigagra/internal/webhook"
 )
 
 type newWizardState struct {
 	AssigneeID   *int64
+	EpicID       *int64
 	ProjectID    int64
 	AwaitingText bool
 }
@@ -50,7 +60,94 @@ var (
 	newWizard   = make(map[int64]newWizardState)
 )
 
+// loginWizardState tracks a /login conversation. The password is never kept here.
+type loginWizardState struct {
+	Username         string
+	AwaitingPassword bool
+}
+
+var (
+	loginWizardMu sync.Mutex
+	loginWizard   = make(map[int64]loginWizardState)
+)
+
+// linkNonce is a one-time /start payload that continues in private chat a /link sent to a group.
+type linkNonce struct {
+	Expires    time.Time
+	TelegramID int64
+}
+
+// linkNonceTTL is how long a deep link from a group /link stays usable.
+const linkNonceTTL = 10 * time.Minute
+
+// linkNoncePrefix marks /start payloads that carry a link nonce.
+const linkNoncePrefix = "link_"
+
+var (
+	linkNoncesMu sync.Mutex
+	linkNonces   = make(map[string]linkNonce)
+)
+
+// inlineCacheKey identifies cached inline results: results depend on who asks.
+type inlineCacheKey struct {
+	Query      string
+	TelegramID int64
+}
+
+// inlineCacheEntry holds inline results until they expire.
+type inlineCacheEntry struct {
+	Expires time.Time
+	Results []telego.InlineQueryResult
+}
+
+const (
+	// inlineCacheTTL is how long inline results are reused, both by the bot and by Telegram.
+	inlineCacheTTL = time.Minute
+	// inlineQueryMinLength is the shortest inline query that is searched.
+	inlineQueryMinLength = 2
+	// inlineSearchProjects caps how many of the user's projects one inline query searches.
+	inlineSearchProjects = 10
+	// inlineSearchTimeout bounds one inline search, so Telegram gets an answer before it gives up.
+	inlineSearchTimeout = 5 * time.Second
+	// inlineResultsLimit is the most results Telegram accepts in one answer.
+	inlineResultsLimit = 50
+)
+
+var (
+	inlineCacheMu sync.Mutex
+	inlineCache   = make(map[inlineCacheKey]inlineCacheEntry)
+)
+
+// projectAccessKey identifies a cached answer to whether a Taiga user can read a project.
+type projectAccessKey struct {
+	TelegramID  int64
+	TaigaUserID int64
+	ProjectID   int64
+}
+
+// projectAccessEntry holds a project access answer until it expires.
+type projectAccessEntry struct {
+	Expires time.Time
+	Allowed bool
+}
+
+// projectAccessTTL is how long a project access check is reused for webhook notifications.
+const projectAccessTTL = 10 * time.Minute
+
+var (
+	projectAccessMu sync.Mutex
+	projectAccess   = make(map[projectAccessKey]projectAccessEntry)
+)
+
+// secretCommands carry Taiga tokens in their arguments and are only accepted in private chats.
+var secretCommands = []string{"link", "adminlinkid"}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "rotate-token-key" {
+		rotateTokenKey()
+		return
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
@@ -59,10 +156,11 @@ func main() {
 		log.Fatalf("load config: %v", err)
 	}
 
-	store, err := storage.New(cfg.StoragePath)
+	store, err := openStore(cfg)
 	if err != nil {
 		log.Fatalf("open storage: %v", err)
 	}
+	defer store.Close()
 
 	bot, err := telego.NewBot(cfg.TelegramToken)
 	if err != nil {
@@ -81,6 +179,11 @@ func main() {
 
 	defer func() { _ = bh.Stop() }()
 
+	botUser, err := bot.GetMe(ctx)
+	if err != nil {
+		log.Fatalf("get bot user: %v", err)
+	}
+
 	bh.Handle(func(ctx *th.Context, update telego.Update) error {
 		if update.Message != nil && update.Message.From != nil {
 			_ = store.UpsertTelegramUsername(update.Message.From.Username, update.Message.From.ID)
@@ -116,18 +219,46 @@ func main() {
 		return id, nil
 	}
 
+	metadataCache := taiga.NewMetadataCache(cfg.MetadataTTL)
+	tokenSources := taiga.NewTokenSources()
+
 	newTaigaClient := func(telegramID int64) (*taiga.Client, error) {
 		link, ok := store.Get(telegramID)
 		if !ok {
 			return nil, fmt.Errorf("Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
 		}
 
-		return taiga.NewClientWithTokens(cfg.TaigaBaseURL, link.TaigaToken, link.TaigaRefresh, func(authToken, refreshToken string) {
-			updated := link
-			updated.TaigaToken = authToken
-			updated.TaigaRefresh = refreshToken
-			_ = store.Save(updated)
-		})
+		client, err := newUserClient(cfg.TaigaBaseURL, store, tokenSources, link)
+		if err != nil {
+			return nil, err
+		}
+
+		client.SetMetadataCache(metadataCache, link.TaigaUserID)
+
+		return client, nil
+	}
+
+	// resolveItem looks an item up by ref, using the chat's bound project for a bare #ref.
+	resolveItem := func(client *taiga.Client, chatID int64, ref itemRef) (taiga.Item, error) {
+		project := ref.Project
+		if project == "" {
+			bound, ok := store.GetChatProject(chatID)
+			if !ok {
+				return taiga.Item{}, fmt.Errorf("Чат не привʼязаний до проєкту. Вкажи <project>#%d або використай /bindproject <project>.", ref.Ref)
+			}
+
+			project = bound.Slug
+			if project == "" {
+				project = strconv.FormatInt(bound.ProjectID, 10)
+			}
+		}
+
+		item, err := client.ResolveItem(context.Background(), project, ref.Ref)
+		if err != nil {
+			return item, fmt.Errorf("Не вдалося знайти %s#%d: %v", project, ref.Ref, err)
+		}
+
+		return item, nil
 	}
 
 	isProjectAdmin := func(ctx context.Context, telegramID, projectID int64) (bool, error) {
@@ -136,12 +267,7 @@ func main() {
 			return false, errors.New("Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
 		}
 
-		client, err := taiga.NewClientWithTokens(cfg.TaigaBaseURL, link.TaigaToken, link.TaigaRefresh, func(authToken, refreshToken string) {
-			updated := link
-			updated.TaigaToken = authToken
-			updated.TaigaRefresh = refreshToken
-			_ = store.Save(updated)
-		})
+		client, err := newUserClient(cfg.TaigaBaseURL, store, tokenSources, link)
 		if err != nil {
 			return false, err
 		}
@@ -162,11 +288,68 @@ func main() {
 		return false, nil
 	}
 
+	secretInGroup := func(_ context.Context, update telego.Update) bool {
+		message := update.Message
+		if message == nil || message.Chat.Type == "private" {
+			return false
+		}
+
+		command, _, _ := tu.ParseCommand(message.Text)
+
+		return slices.Contains(secretCommands, command)
+	}
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		command, _, args := tu.ParseCommand(message.Text)
+
+		if len(args) > 0 {
+			scrubSecretMessage(ctx, message)
+		}
+
+		if command != "link" || message.From == nil {
+			if len(args) == 0 {
+				return sendText(ctx, message.Chat.ID, "Цю команду можна використовувати лише в приватному чаті")
+			}
+
+			return nil
+		}
+
+		nonce := issueLinkNonce(message.From.ID, time.Now())
+		deepLink := fmt.Sprintf("https://t.me/%s?start=%s%s", botUser.Username, linkNoncePrefix, nonce)
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Привʼязка акаунта Taiga відбувається в особистому чаті. Продовж за посиланням (діє %d хв): %s", int(linkNonceTTL.Minutes()), deepLink))
+	}, secretInGroup)
+
+	linkNonceStart := func(_ context.Context, update telego.Update) bool {
+		message := update.Message
+		if message == nil || message.From == nil || message.Chat.Type != "private" {
+			return false
+		}
+
+		command, _, args := tu.ParseCommand(message.Text)
+
+		return command == "start" && len(args) == 1 && strings.HasPrefix(args[0], linkNoncePrefix)
+	}
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		_, _, args := tu.ParseCommand(message.Text)
+
+		if !consumeLinkNonce(strings.TrimPrefix(args[0], linkNoncePrefix), message.From.ID, time.Now()) {
+			return sendText(ctx, message.Chat.ID, "Посилання для привʼязки недійсне або прострочене. Надішли /login або /link <auth_token> <refresh_token>.")
+		}
+
+		loginWizardMu.Lock()
+		loginWizard[message.From.ID] = loginWizardState{}
+		loginWizardMu.Unlock()
+
+		return sendText(ctx, message.Chat.ID, "Продовжимо привʼязку. Надішли логін або email Taiga (/cancel — скасувати) або токени командою /link <auth_token> <refresh_token>.")
+	}, linkNonceStart)
+
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
 		return sendText(
 			ctx,
 			message.Chat.ID,
-			"Команди:\n/link <auth_token> <refresh_token>\n/me\n/unlink\n/projects\n/new\n/cancel\n/notifyhere\n/notifychat <chat_id>\n/notifypm\n/watch <project_id>\n/unwatch <project_id>\n/watches\n/map <project_id> <taiga_user_id>  (reply)\n/mapid <project_id> <telegram_user_id|@username> <taiga_user_id>\n/mappings <project_id>\n/adminlinkid <project_id> <telegram_user_id|@username> <auth_token> <refresh_token>\n/task <project_id> [taiga_user_id] <subject> [| description]  (створює завдання)\n/taskto <project_id> <taiga_user_id> <subject> [| description]  (створює завдання)\n/my [project_id]  (показує завдання)\n/myfor <project_id> <telegram_user_id|@username>  (показує завдання іншого користувача, лише для адміна проєкту)",
+			"Команди:\n/login  (вхід за логіном і паролем Taiga)\n/link <auth_token> <refresh_token>\n/me\n/unlink\n/projects\n/new\n/cancel\n/notifyhere\n/notifychat <chat_id>\n/notifypm\n/watch <project_id>\n/unwatch <project_id>\n/watches\n/map <project_id> <taiga_user_id>  (reply)\n/mapid <project_id> <telegram_user_id|@username> <taiga_user_id>\n/mappings <project_id>\n/adminlinkid <project_id> <telegram_user_id|@username> <auth_token> <refresh_token>\n/task <project_id> [taiga_user_id] <subject> [| description]  (створює завдання)\n/taskto <project_id> <taiga_user_id> <subject> [| description]  (створює завдання)\n/issue <project_id> <subject> [| description]  (створює запит)\n/epics <project_id>  (показує епіки та прогрес)\n/sprint <project_id>  (показує поточний спринт)\n/tosprint <project>#<ref>  (переносить завдання в поточний спринт)\n/comment <project>#<ref> <text>  (додає коментар; або дай відповідь на сповіщення)\n/status <project>#<ref> [статус]  (змінює статус)\n/show <project>#<ref>  (показує картку)\n/find <project_id|slug> <текст>  (шукає завдання, задачі й запити)\n/bindproject <project_id|slug>  (привʼязує чат до проєкту, щоб писати просто #<ref>; у групі — лише адміністратор чату)\n/unbindproject\nФото чи файл з підписом <project>#<ref> [опис] або відповіддю на сповіщення додається як вкладення\n/my [project_id]  (показує завдання)\n/myfor <project_id> <telegram_user_id|@username>  (показує завдання іншого користувача, лише для адміна проєкту)",
 		)
 	}, th.CommandEqual("start"))
 
@@ -207,7 +390,7 @@ func main() {
 
 		admin, err := isProjectAdmin(ctx, message.From.ID, projectID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %s", describeTaigaError(err)))
 		}
 
 		if !admin {
@@ -216,12 +399,12 @@ func main() {
 
 		taigaClient, err := taiga.NewClientWithTokens(cfg.TaigaBaseURL, authToken, refreshToken, nil)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
 		me, err := taigaClient.GetMe(ctx)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося перевірити токени Taiga: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося перевірити токени Taiga: %s", describeTaigaError(err)))
 		}
 
 		link := storage.UserLink{
@@ -231,10 +414,19 @@ func main() {
 			TaigaUserID:   me.ID,
 			TaigaUserName: me.FullName,
 		}
-		if err := store.Save(link); err != nil {
+
+		// The link and the project mapping are saved together or not at all.
+		err = store.Transaction(func(tx *storage.Tx) error {
+			tx.Save(link)
+
+			return tx.SetProjectUserMapping(projectID, targetTelegramID, me.ID)
+		})
+		if err != nil {
 			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося зберегти привʼязку: %v", err))
 		}
 
+		tokenSources.Forget(link.TelegramID)
+
 		_ = ctx.Bot().DeleteMessage(ctx, &telego.DeleteMessageParams{ChatID: tu.ID(message.Chat.ID), MessageID: message.MessageID})
 
 		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Збережено привʼязку для Telegram %d -> Taiga %d", targetTelegramID, me.ID))
@@ -268,7 +460,7 @@ func main() {
 
 		admin, err := isProjectAdmin(ctx, message.From.ID, projectID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %s", describeTaigaError(err)))
 		}
 
 		if !admin {
@@ -312,7 +504,7 @@ func main() {
 
 		admin, err := isProjectAdmin(ctx, message.From.ID, projectID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %s", describeTaigaError(err)))
 		}
 
 		if !admin {
@@ -340,7 +532,7 @@ func main() {
 
 		admin, err := isProjectAdmin(ctx, message.From.ID, projectID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %s", describeTaigaError(err)))
 		}
 
 		if !admin {
@@ -376,12 +568,12 @@ func main() {
 
 		client, err := newTaigaClient(message.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
 		projects, err := client.ListProjects(context.Background())
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список проєктів: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список проєктів: %s", describeTaigaError(err)))
 		}
 
 		if len(projects) == 0 {
@@ -469,7 +661,7 @@ func main() {
 			client, err := newTaigaClient(telegramID)
 			if err != nil {
 				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Помилка"))
-				_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), fmt.Sprintf("Помилка клієнта Taiga: %v", err)))
+				_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err))))
 
 				return nil
 			}
@@ -477,7 +669,7 @@ func main() {
 			memberships, err := client.ListMemberships(context.Background(), projectID)
 			if err != nil {
 				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Помилка"))
-				_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), fmt.Sprintf("Не вдалося отримати користувачів проєкту: %v", err)))
+				_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), fmt.Sprintf("Не вдалося отримати користувачів проєкту: %s", describeTaigaError(err))))
 
 				return nil
 			}
@@ -541,9 +733,97 @@ func main() {
 				assigneeID = &a
 			}
 
+			var epics []taiga.Epic
+
+			client, err := newTaigaClient(telegramID)
+			if err == nil {
+				epics, err = client.ListEpics(context.Background(), taiga.ListEpicsParams{ProjectID: projectID})
+				if err != nil {
+					log.Printf("list epics for wizard: project_id=%d err=%v", projectID, err)
+				}
+			}
+
+			rows := make([][]telego.InlineKeyboardButton, 0, len(epics)+2)
+			rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton("Без епіка").WithCallbackData(fmt.Sprintf("new:epic:%d:%d:0", projectID, assigneeRaw))))
+
+			for _, epic := range epics {
+				if epic.IsClosed {
+					continue
+				}
+
+				data := fmt.Sprintf("new:epic:%d:%d:%d", projectID, assigneeRaw, epic.ID)
+
+				rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton(fmt.Sprintf("#%d %s", epic.Ref, epic.Subject)).WithCallbackData(data)))
+			}
+
+			if len(rows) == 1 {
+				newWizardMu.Lock()
+
+				newWizard[telegramID] = newWizardState{ProjectID: projectID, AssigneeID: assigneeID, AwaitingText: true}
+
+				newWizardMu.Unlock()
+
+				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Ок"))
+				_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), "Введи тему та (необовʼязково) опис у форматі: Тема | опис"))
+
+				return nil
+			}
+
+			newWizardMu.Lock()
+
+			newWizard[telegramID] = newWizardState{ProjectID: projectID, AssigneeID: assigneeID}
+
+			newWizardMu.Unlock()
+
+			rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton("Скасувати").WithCallbackData("new:cancel")))
+			_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), "Обери епік:").WithReplyMarkup(tu.InlineKeyboard(rows...)))
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Ок"))
+
+			return nil
+
+		case "epic":
+			deleteInlineMessage()
+
+			if len(parts) < 5 {
+				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректні дані"))
+				return nil
+			}
+
+			projectID, err := strconv.ParseInt(parts[2], 10, 64)
+			if err != nil || projectID <= 0 {
+				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректний проєкт"))
+				return nil
+			}
+
+			assigneeRaw, err := strconv.ParseInt(parts[3], 10, 64)
+			if err != nil || assigneeRaw < 0 {
+				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректний виконавець"))
+				return nil
+			}
+
+			epicRaw, err := strconv.ParseInt(parts[4], 10, 64)
+			if err != nil || epicRaw < 0 {
+				_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректний епік"))
+				return nil
+			}
+
+			var assigneeID *int64
+			if assigneeRaw != 0 {
+				a := assigneeRaw
+
+				assigneeID = &a
+			}
+
+			var epicID *int64
+			if epicRaw != 0 {
+				e := epicRaw
+
+				epicID = &e
+			}
+
 			newWizardMu.Lock()
 
-			newWizard[telegramID] = newWizardState{ProjectID: projectID, AssigneeID: assigneeID, AwaitingText: true}
+			newWizard[telegramID] = newWizardState{ProjectID: projectID, AssigneeID: assigneeID, EpicID: epicID, AwaitingText: true}
 
 			newWizardMu.Unlock()
 
@@ -558,199 +838,240 @@ func main() {
 		return nil
 	}, th.AnyCallbackQueryWithMessage(), th.CallbackDataPrefix("new:"))
 
-	notCommand := func(_ context.Context, update telego.Update) bool {
-		if update.Message == nil {
+	awaitingLogin := func(_ context.Context, update telego.Update) bool {
+		message := update.Message
+		if message == nil || message.From == nil || message.Chat.Type != "private" {
 			return false
 		}
 
-		text := strings.TrimSpace(update.Message.Text)
-		if text == "" {
+		if strings.HasPrefix(strings.TrimSpace(message.Text), "/") {
 			return false
 		}
 
-		return !strings.HasPrefix(text, "/")
+		loginWizardMu.Lock()
+		defer loginWizardMu.Unlock()
+
+		_, ok := loginWizard[message.From.ID]
+
+		return ok
 	}
 
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return nil
-		}
+		loginWizardMu.Lock()
+		state := loginWizard[message.From.ID]
+		loginWizardMu.Unlock()
 
-		newWizardMu.Lock()
+		text := strings.TrimSpace(message.Text)
 
-		state, ok := newWizard[message.From.ID]
+		if !state.AwaitingPassword {
+			if text == "" {
+				return sendText(ctx, message.Chat.ID, "Надішли логін або email Taiga текстом. /cancel — скасувати.")
+			}
 
-		newWizardMu.Unlock()
+			loginWizardMu.Lock()
+			loginWizard[message.From.ID] = loginWizardState{Username: text, AwaitingPassword: true}
+			loginWizardMu.Unlock()
 
-		if !ok || !state.AwaitingText {
-			return nil
+			return sendText(ctx, message.Chat.ID, "Тепер надішли пароль. Повідомлення з паролем буде одразу видалено.")
 		}
 
-		subject, description := splitSubjectDescription(strings.TrimSpace(message.Text))
-		if strings.TrimSpace(subject) == "" {
-			return sendText(ctx, message.Chat.ID, "Потрібна тема")
-		}
+		// The password must not stay in the chat history, whatever happens next.
+		_ = ctx.Bot().DeleteMessage(ctx, &telego.DeleteMessageParams{ChatID: tu.ID(message.Chat.ID), MessageID: message.MessageID})
 
-		client, err := newTaigaClient(message.From.ID)
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
-		}
+		loginWizardMu.Lock()
+		delete(loginWizard, message.From.ID)
+		loginWizardMu.Unlock()
 
-		req := taiga.UserStoryCreateRequest{
-			ProjectID:   state.ProjectID,
-			Subject:     subject,
-			Description: description,
-			Assigned:    state.AssigneeID,
+		if message.Text == "" {
+			return sendText(ctx, message.Chat.ID, "Пароль має бути текстом. Почни знову: /login")
 		}
 
-		us, err := client.CreateUserStory(context.Background(), req)
+		link, err := loginLink(ctx, cfg, message.From.ID, state.Username, message.Text)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити завдання: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося увійти в Taiga: %s. Почни знову: /login", describeTaigaError(err)))
 		}
 
-		newWizardMu.Lock()
-		delete(newWizard, message.From.ID)
-		newWizardMu.Unlock()
+		if err := store.Save(link); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося зберегти привʼязку: %v", err))
+		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s", us.Ref, us.Subject))
-	}, notCommand)
+		tokenSources.Forget(link.TelegramID)
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		args := strings.TrimSpace(commandArgs(message.Text))
-		if args == "" {
-			return sendText(ctx, message.Chat.ID, "Використання: /link <auth_token> <refresh_token>")
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Привʼязано до користувача Taiga: %s (%d)", link.TaigaUserName, link.TaigaUserID))
+	}, awaitingLogin)
+
+	replyToNotification := func(_ context.Context, update telego.Update) bool {
+		message := update.Message
+		if message == nil || message.ReplyToMessage == nil {
+			return false
 		}
 
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Не можу привʼязати: відсутня інформація про користувача")
+		text := strings.TrimSpace(message.Text)
+		if text == "" || strings.HasPrefix(text, "/") {
+			return false
 		}
 
-		parts := strings.Fields(args)
-		if len(parts) != 2 {
-			return sendText(ctx, message.Chat.ID, "Використання: /link <auth_token> <refresh_token>")
+		_, ok := store.GetNotificationTarget(message.Chat.ID, message.ReplyToMessage.MessageID)
+
+		return ok
+	}
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return nil
 		}
-		authToken := parts[0]
-		refreshToken := parts[1]
 
-		client, err := taiga.NewClientWithTokens(cfg.TaigaBaseURL, authToken, refreshToken, nil)
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+		target, ok := store.GetNotificationTarget(message.Chat.ID, message.ReplyToMessage.MessageID)
+		if !ok {
+			return nil
 		}
 
-		me, err := client.GetMe(context.Background())
+		client, err := newTaigaClient(message.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка авторизації в Taiga: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
-		link := storage.UserLink{
-			TelegramID:     message.From.ID,
-			TaigaToken:     authToken,
-			TaigaRefresh:   refreshToken,
-			TaigaUserID:    me.ID,
-			TaigaUserName:  me.FullName,
-			LastTaskStates: nil,
-		}
-		if err := store.Save(link); err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося зберегти привʼязку: %v", err))
+		if err := client.AddComment(context.Background(), taiga.ItemKind(target.Kind), target.ItemID, message.Text); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося додати коментар: %s", describeTaigaError(err)))
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Привʼязано до користувача Taiga: %s (%d)", me.FullName, me.ID))
-	}, th.CommandEqual("link"))
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Коментар додано до #%d", target.Ref))
+	}, replyToNotification)
 
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
 		if message.From == nil {
 			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
 		}
 
-		link, ok := store.Get(message.From.ID)
-		if !ok {
+		if _, ok := store.Get(message.From.ID); !ok {
 			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Привʼязаний користувач Taiga: %s (%d)", link.TaigaUserName, link.TaigaUserID))
-	}, th.CommandEqual("me"))
-
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		refToken, text := splitFirstField(commandArgs(message.Text))
+		if refToken == "" || text == "" {
+			return sendText(ctx, message.Chat.ID, "Використання: /comment <project>#<ref> <text>")
 		}
 
-		if _, ok := store.Get(message.From.ID); !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		ref, err := parseItemRef(refToken)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
 		client, err := newTaigaClient(message.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
-		projects, err := client.ListProjects(context.Background())
+		item, err := resolveItem(client, message.Chat.ID, ref)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список проєктів: %v", err))
+			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
-		if len(projects) == 0 {
-			return sendText(ctx, message.Chat.ID, "Немає проєктів")
+		if err := client.AddComment(context.Background(), item.Kind, item.ID, text); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося додати коментар: %s", describeTaigaError(err)))
 		}
 
-		var b strings.Builder
-		for _, p := range projects {
-			b.WriteString(fmt.Sprintf("%d %s (%s)\n", p.ID, p.Name, p.Slug))
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Коментар додано до #%d %s", item.Ref, item.Subject))
+	}, th.CommandEqual("comment"))
+
+	hasAttachment := func(_ context.Context, update telego.Update) bool {
+		message := update.Message
+		if message == nil {
+			return false
 		}
 
-		return sendText(ctx, message.Chat.ID, b.String())
-	}, th.CommandEqual("projects"))
+		return len(message.Photo) > 0 || message.Document != nil
+	}
 
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
 		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+			return nil
 		}
-		err := store.Delete(message.From.ID)
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося відвʼязати: %v", err))
+
+		var (
+			kind        taiga.ItemKind
+			itemID      int64
+			projectID   int64
+			ref         int64
+			captionRef  itemRef
+			description = strings.TrimSpace(message.Caption)
+		)
+
+		if message.ReplyToMessage != nil {
+			if target, ok := store.GetNotificationTarget(message.Chat.ID, message.ReplyToMessage.MessageID); ok {
+				kind = taiga.ItemKind(target.Kind)
+				itemID = target.ItemID
+				projectID = target.ProjectID
+				ref = target.Ref
+			}
 		}
 
-		return sendText(ctx, message.Chat.ID, "Відвʼязано")
-	}, th.CommandEqual("unlink"))
+		if itemID == 0 {
+			refToken, rest := splitFirstField(message.Caption)
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+			parsed, err := parseItemRef(refToken)
+			if err != nil {
+				if message.Chat.Type == "private" {
+					return sendText(ctx, message.Chat.ID, "Щоб додати вкладення, вкажи в підписі <project>#<ref> або дай відповідь на сповіщення")
+				}
+
+				return nil
+			}
+
+			captionRef = parsed
+			ref = parsed.Ref
+			description = rest
 		}
 
 		if _, ok := store.Get(message.From.ID); !ok {
 			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
 		}
 
-		chatID := message.Chat.ID
-		err := store.SetNotifyChat(message.From.ID, &chatID)
+		client, err := newTaigaClient(message.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося встановити чат для сповіщень: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Сповіщення надсилатимуться сюди (%d)", message.Chat.ID))
-	}, th.CommandEqual("notifyhere"))
+		switch {
+		case itemID == 0:
+			item, err := resolveItem(client, message.Chat.ID, captionRef)
+			if err != nil {
+				return sendText(ctx, message.Chat.ID, err.Error())
+			}
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
-		}
+			kind = item.Kind
+			itemID = item.ID
+			projectID = item.Project
 
-		if _, ok := store.Get(message.From.ID); !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		case projectID == 0:
+			item, err := client.GetItem(context.Background(), kind, itemID)
+			if err != nil {
+				return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося знайти #%d: %v", ref, err))
+			}
+
+			projectID = item.Project
 		}
 
-		chatID, err := parseChatID(commandArgs(message.Text))
+		fileID, fileName := attachmentFile(message)
+
+		content, err := downloadTelegramFile(ctx, ctx.Bot(), fileID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, err.Error())
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося завантажити файл з Telegram: %v", err))
 		}
 
-		if err := store.SetNotifyChat(message.From.ID, &chatID); err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося встановити чат для сповіщень: %v", err))
+		attachment, err := client.CreateAttachment(context.Background(), kind, taiga.AttachmentUpload{
+			ProjectID:   projectID,
+			ObjectID:    itemID,
+			FileName:    fileName,
+			Description: description,
+			Content:     bytes.NewReader(content),
+		})
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося додати вкладення: %s", describeTaigaError(err)))
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Сповіщення надсилатимуться в чат %d", chatID))
-	}, th.CommandEqual("notifychat"))
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Вкладення %s додано до #%d", attachment.Name, ref))
+	}, hasAttachment)
 
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
 		if message.From == nil {
@@ -760,280 +1081,1201 @@ func main() {
 		if _, ok := store.Get(message.From.ID); !ok {
 			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
 		}
-		err := store.SetNotifyChat(message.From.ID, nil)
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося встановити приватні сповіщення: %v", err))
-		}
 
-		return sendText(ctx, message.Chat.ID, "Сповіщення надсилатимуться в приватний чат")
-	}, th.CommandEqual("notifypm"))
+		refToken, statusName := splitFirstField(commandArgs(message.Text))
+		if refToken == "" {
+			return sendText(ctx, message.Chat.ID, "Використання: /status <project>#<ref> [статус]")
+		}
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		ref, err := parseItemRef(refToken)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
-		if _, ok := store.Get(message.From.ID); !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
-		projectID, err := parseRequiredProjectID(commandArgs(message.Text))
+		item, err := resolveItem(client, message.Chat.ID, ref)
 		if err != nil {
 			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
-		if err := store.AddWatchedProject(message.From.ID, projectID); err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося підписатися: %v", err))
-		}
+		if statusName == "" {
+			statuses, err := client.ListStatuses(context.Background(), item.Kind, item.Project)
+			if err != nil {
+				return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати статуси: %s", describeTaigaError(err)))
+			}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Підписано на проєкт %d", projectID))
-	}, th.CommandEqual("watch"))
+			rows := make([][]telego.InlineKeyboardButton, 0, len(statuses))
+			for _, st := range statuses {
+				data := fmt.Sprintf("status:%s:%d:%d", item.Kind, item.ID, st.ID)
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
-		}
+				rows = append(rows, tu.InlineKeyboardRow(tu.InlineKeyboardButton(st.Name).WithCallbackData(data)))
+			}
 
-		if _, ok := store.Get(message.From.ID); !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+			text := fmt.Sprintf("#%d %s [%s]\nОбери новий статус:", item.Ref, item.Subject, item.StatusExtraInfo.Name)
+			_, err = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(message.Chat.ID), text).WithReplyMarkup(tu.InlineKeyboard(rows...)))
+
+			return err
 		}
 
-		projectID, err := parseRequiredProjectID(commandArgs(message.Text))
+		status, err := client.ResolveStatus(context.Background(), item.Kind, item.Project, statusName)
 		if err != nil {
 			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
-		if err := store.RemoveWatchedProject(message.From.ID, projectID); err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося відписатися: %v", err))
+		if err := setItemStatus(context.Background(), client, item.Kind, item.ID, status.ID); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося змінити статус: %s", describeTaigaError(err)))
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Відписано від проєкту %d", projectID))
-	}, th.CommandEqual("unwatch"))
-
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
-		}
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Статус #%d %s змінено на %s", item.Ref, item.Subject, status.Name))
+	}, th.CommandEqual("status"))
 
-		link, ok := store.Get(message.From.ID)
+	bh.HandleCallbackQuery(func(ctx *th.Context, query telego.CallbackQuery) error {
+		msg, ok := query.Message.(*telego.Message)
 		if !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
-		}
-
-		if len(link.WatchedProjects) == 0 {
-			return sendText(ctx, message.Chat.ID, "Немає проєктів у підписках")
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Повідомлення недоступне"))
+			return nil
 		}
 
-		var b strings.Builder
-		b.WriteString("Підписки на проєкти:\n")
-
-		for _, pid := range link.WatchedProjects {
-			b.WriteString(fmt.Sprintf("%d\n", pid))
+		parts := strings.Split(query.Data, ":")
+		if len(parts) != 4 {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректні дані"))
+			return nil
 		}
 
-		return sendText(ctx, message.Chat.ID, b.String())
-	}, th.CommandEqual("watches"))
+		kind := taiga.ItemKind(parts[1])
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		itemID, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || itemID <= 0 {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректні дані"))
+			return nil
 		}
 
-		if _, ok := store.Get(message.From.ID); !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		statusID, err := strconv.ParseInt(parts[3], 10, 64)
+		if err != nil || statusID <= 0 {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректний статус"))
+			return nil
 		}
 
-		projectID, assigneeID, subject, description, err := parseTaskTo(commandArgs(message.Text))
+		client, err := newTaigaClient(query.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, err.Error())
-		}
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Помилка"))
+			_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(msg.Chat.ID), fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err))))
 
-		client, err := newTaigaClient(message.From.ID)
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+			return nil
 		}
 
-		req := taiga.UserStoryCreateRequest{
-			ProjectID:   projectID,
-			Subject:     subject,
-			Description: description,
-			Assigned:    &assigneeID,
+		if err := setItemStatus(context.Background(), client, kind, itemID, statusID); err != nil {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Помилка"))
+			_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(msg.Chat.ID), fmt.Sprintf("Не вдалося змінити статус: %s", describeTaigaError(err))))
+
+			return nil
 		}
 
-		us, err := client.CreateUserStory(context.Background(), req)
+		_ = ctx.Bot().DeleteMessage(ctx, &telego.DeleteMessageParams{ChatID: tu.ID(msg.Chat.ID), MessageID: msg.MessageID})
+		_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Статус змінено"))
+
+		item, err := client.GetItem(context.Background(), kind, itemID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити завдання: %v", err))
+			return nil
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s", us.Ref, us.Subject))
-	}, th.CommandEqual("taskto"))
+		_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(msg.Chat.ID), fmt.Sprintf("Статус #%d %s змінено на %s", item.Ref, item.Subject, item.StatusExtraInfo.Name)))
+
+		return nil
+	}, th.AnyCallbackQueryWithMessage(), th.CallbackDataPrefix("status:"))
 
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
 		if message.From == nil {
 			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
 		}
 
-		link, ok := store.Get(message.From.ID)
-		if !ok {
+		if _, ok := store.Get(message.From.ID); !ok {
 			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
 		}
 
-		projectID, assigneeID, subject, description, err := parseTaskWithOptionalAssignee(commandArgs(message.Text))
+		refToken := commandArgs(message.Text)
+		if refToken == "" {
+			return sendText(ctx, message.Chat.ID, "Використання: /show <project>#<ref>")
+		}
+
+		ref, err := parseItemRef(refToken)
 		if err != nil {
 			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
 		client, err := newTaigaClient(message.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
-		}
-
-		if assigneeID == nil {
-			assigneeID = &link.TaigaUserID
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
-		req := taiga.UserStoryCreateRequest{
-			ProjectID:   projectID,
-			Subject:     subject,
-			Description: description,
-			Assigned:    assigneeID,
+		item, err := resolveItem(client, message.Chat.ID, ref)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
 		}
 
-		us, err := client.CreateUserStory(context.Background(), req)
+		card, err := loadItemCard(context.Background(), client, item.Kind, item.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити завдання: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати #%d: %v", item.Ref, err))
 		}
 
-		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s", us.Ref, us.Subject))
-	}, th.CommandEqual("task"))
+		return sendText(ctx, message.Chat.ID, formatItemCard(card, cfg.TaigaWebURL))
+	}, th.CommandEqual("show"))
 
 	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
 		if message.From == nil {
 			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
 		}
 
-		link, ok := store.Get(message.From.ID)
-		if !ok {
-			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
-		}
-
-		projectID, err := parseOptionalProjectID(commandArgs(message.Text))
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, err.Error())
+		project, text := splitFirstField(commandArgs(message.Text))
+		if project == "" || strings.TrimSpace(text) == "" {
+			return sendText(ctx, message.Chat.ID, "Використання: /find <project_id|slug> <текст>")
 		}
 
 		client, err := newTaigaClient(message.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
 		}
 
-		assigned := link.TaigaUserID
-		stories, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{ProjectID: projectID, AssignedTo: &assigned})
+		found, err := client.FindProject(context.Background(), project)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список завдання: %v", err))
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося знайти проєкт %s: %s", project, describeTaigaError(err)))
 		}
 
-		if len(stories) == 0 {
-			return sendText(ctx, message.Chat.ID, "Немає завдання")
+		results, err := client.Search(context.Background(), found.ID, text)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося виконати пошук: %s", describeTaigaError(err)))
 		}
 
-		var b strings.Builder
-		for _, us := range stories {
-			b.WriteString(fmt.Sprintf("#%d %s [%s]\n", us.Ref, us.Subject, us.StatusExtraInfo.Name))
+		if results.Len() == 0 {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("У проєкті %s нічого не знайдено за запитом «%s»", found.Name, strings.TrimSpace(text)))
 		}
 
-		return sendText(ctx, message.Chat.ID, b.String())
-	}, th.CommandEqual("my"))
+		body, rows := formatSearchResults(found, results)
 
-	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
-		if message.From == nil {
-			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
-		}
+		_, err = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(message.Chat.ID), body).WithReplyMarkup(tu.InlineKeyboard(rows...)))
 
-		args := strings.TrimSpace(commandArgs(message.Text))
+		return err
+	}, th.CommandEqual("find"))
 
-		parts := strings.Fields(args)
-		if len(parts) != 2 {
-			return sendText(ctx, message.Chat.ID, "Використання: /myfor <project_id> <telegram_user_id|@username>")
+	bh.HandleCallbackQuery(func(ctx *th.Context, query telego.CallbackQuery) error {
+		msg, ok := query.Message.(*telego.Message)
+		if !ok {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Повідомлення недоступне"))
+			return nil
 		}
 
-		projectID, err := strconv.ParseInt(parts[0], 10, 64)
-		if err != nil || projectID <= 0 {
-			return sendText(ctx, message.Chat.ID, "Некоректний id проєкту")
+		parts := strings.Split(query.Data, ":")
+		if len(parts) != 3 {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректні дані"))
+			return nil
 		}
 
-		targetTelegramID, err := resolveTelegramTarget(parts[1])
-		if err != nil {
-			return sendText(ctx, message.Chat.ID, err.Error())
+		kind := taiga.ItemKind(parts[1])
+
+		itemID, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil || itemID <= 0 {
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Некоректні дані"))
+			return nil
 		}
 
-		admin, err := isProjectAdmin(ctx, message.From.ID, projectID)
+		client, err := newTaigaClient(query.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %v", err))
-		}
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Помилка"))
+			_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(msg.Chat.ID), fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err))))
 
-		if !admin {
-			return sendText(ctx, message.Chat.ID, "Недостатньо прав: потрібен адміністратор проєкту в Taiga")
+			return nil
 		}
 
-		client, err := newTaigaClient(message.From.ID)
+		card, err := loadItemCard(context.Background(), client, kind, itemID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %v", err))
+			_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID).WithText("Помилка"))
+			_, _ = ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(msg.Chat.ID), fmt.Sprintf("Не вдалося отримати картку: %s", describeTaigaError(err))))
+
+			return nil
 		}
 
-		taigaUserID, ok := store.GetProjectUserMapping(projectID, targetTelegramID)
+		_ = ctx.Bot().AnswerCallbackQuery(ctx, tu.CallbackQuery(query.ID))
+
+		return sendText(ctx, msg.Chat.ID, formatItemCard(card, cfg.TaigaWebURL))
+	}, th.AnyCallbackQueryWithMessage(), th.CallbackDataPrefix("show:"))
+
+	bh.HandleInlineQuery(func(ctx *th.Context, query telego.InlineQuery) error {
+		answer := tu.InlineQuery(query.ID).WithCacheTime(int(inlineCacheTTL.Seconds())).WithIsPersonal()
+		answer.Results = []telego.InlineQueryResult{}
+
+		link, ok := store.Get(query.From.ID)
 		if !ok {
-			if targetLink, ok2 := store.Get(targetTelegramID); ok2 {
-				taigaUserID = targetLink.TaigaUserID
-				ok = true
-			}
+			nonce := issueLinkNonce(query.From.ID, time.Now())
+			answer.CacheTime = 0
+
+			return ctx.Bot().AnswerInlineQuery(ctx, answer.WithButton(&telego.InlineQueryResultsButton{
+				Text:           "Привʼяжи акаунт Taiga",
+				StartParameter: linkNoncePrefix + nonce,
+			}))
 		}
 
-		if !ok || taigaUserID <= 0 {
-			return sendText(ctx, message.Chat.ID, "Немає мапінгу/привʼязки для цього користувача")
+		text := strings.ToLower(strings.Join(strings.Fields(query.Query), " "))
+		if len([]rune(text)) < inlineQueryMinLength {
+			return ctx.Bot().AnswerInlineQuery(ctx, answer)
 		}
 
-		assigned := taigaUserID
+		key := inlineCacheKey{TelegramID: query.From.ID, Query: text}
+		if results, ok := cachedInlineResults(key, time.Now()); ok {
+			return ctx.Bot().AnswerInlineQuery(ctx, answer.WithResults(results...))
+		}
 
-		stories, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{ProjectID: projectID, AssignedTo: &assigned})
+		client, err := newTaigaClient(query.From.ID)
 		if err != nil {
-			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список завдання: %v", err))
+			log.Printf("inline query: telegram_id=%d err=%v", query.From.ID, err)
+			return ctx.Bot().AnswerInlineQuery(ctx, answer)
 		}
 
-		if len(stories) == 0 {
-			return sendText(ctx, message.Chat.ID, "Немає завдання")
-		}
+		results, err := inlineSearchResults(ctx, client, link.TaigaUserID, text, cfg.TaigaWebURL)
+		if err != nil {
+			log.Printf("inline query: telegram_id=%d err=%v", query.From.ID, err)
+			answer.CacheTime = 0
 
-		var b strings.Builder
-		for _, us := range stories {
-			b.WriteString(fmt.Sprintf("#%d %s [%s]\n", us.Ref, us.Subject, us.StatusExtraInfo.Name))
+			return ctx.Bot().AnswerInlineQuery(ctx, answer)
 		}
 
-		return sendText(ctx, message.Chat.ID, b.String())
-	}, th.CommandEqual("myfor"))
+		storeInlineResults(key, results, time.Now())
 
-	go pollNotifications(ctx, bot, store, cfg.TaigaBaseURL, cfg.PollInterval)
-	go dailyAssignedDigest(ctx, bot, store, cfg.TaigaBaseURL)
+		return ctx.Bot().AnswerInlineQuery(ctx, answer.WithResults(results...))
+	}, th.AnyInlineQuery())
 
-	if err := bh.Start(); err != nil {
-		log.Fatalf("start handler: %v", err)
-	}
-}
+	notCommand := func(_ context.Context, update telego.Update) bool {
+		if update.Message == nil {
+			return false
+		}
 
-func sendText(ctx *th.Context, chatID int64, text string) error {
-	if text == "" {
-		return nil
+		text := strings.TrimSpace(update.Message.Text)
+		if text == "" {
+			return false
+		}
+
+		return !strings.HasPrefix(text, "/")
 	}
 
-	for _, chunk := range splitMessage(text, 3500) {
-		_, err := ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), chunk))
-		if err != nil {
-			return err
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return nil
 		}
-	}
 
-	return nil
-}
+		newWizardMu.Lock()
+
+		state, ok := newWizard[message.From.ID]
+
+		newWizardMu.Unlock()
+
+		if !ok || !state.AwaitingText {
+			return nil
+		}
+
+		subject, description := splitSubjectDescription(strings.TrimSpace(message.Text))
+		if strings.TrimSpace(subject) == "" {
+			return sendText(ctx, message.Chat.ID, "Потрібна тема")
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		req := taiga.UserStoryCreateRequest{
+			ProjectID:   state.ProjectID,
+			Subject:     subject,
+			Description: description,
+			Assigned:    state.AssigneeID,
+		}
+
+		us, err := client.CreateUserStory(context.Background(), req)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити завдання: %s", describeTaigaError(err)))
+		}
+
+		newWizardMu.Lock()
+		delete(newWizard, message.From.ID)
+		newWizardMu.Unlock()
+
+		if state.EpicID != nil {
+			if _, err := client.LinkUserStoryToEpic(context.Background(), *state.EpicID, us.ID); err != nil {
+				return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s, але не вдалося додати його до епіка: %v", us.Ref, us.Subject, err))
+			}
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s", us.Ref, us.Subject))
+	}, notCommand)
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		args := strings.TrimSpace(commandArgs(message.Text))
+		if args == "" {
+			return sendText(ctx, message.Chat.ID, "Використання: /link <auth_token> <refresh_token>")
+		}
+
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Не можу привʼязати: відсутня інформація про користувача")
+		}
+
+		parts := strings.Fields(args)
+		if len(parts) != 2 {
+			return sendText(ctx, message.Chat.ID, "Використання: /link <auth_token> <refresh_token>")
+		}
+		authToken := parts[0]
+		refreshToken := parts[1]
+
+		client, err := taiga.NewClientWithTokens(cfg.TaigaBaseURL, authToken, refreshToken, nil)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		me, err := client.GetMe(context.Background())
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка авторизації в Taiga: %s", describeTaigaError(err)))
+		}
+
+		link := storage.UserLink{
+			TelegramID:     message.From.ID,
+			TaigaToken:     authToken,
+			TaigaRefresh:   refreshToken,
+			TaigaUserID:    me.ID,
+			TaigaUserName:  me.FullName,
+			LastTaskStates: nil,
+		}
+		if err := store.Save(link); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося зберегти привʼязку: %v", err))
+		}
+
+		tokenSources.Forget(link.TelegramID)
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Привʼязано до користувача Taiga: %s (%d)", me.FullName, me.ID))
+	}, th.CommandEqual("link"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if message.Chat.Type != "private" {
+			return sendText(ctx, message.Chat.ID, "Цю команду можна використовувати лише в приватному чаті")
+		}
+
+		loginWizardMu.Lock()
+		loginWizard[message.From.ID] = loginWizardState{}
+		loginWizardMu.Unlock()
+
+		return sendText(ctx, message.Chat.ID, "Надішли логін або email Taiga. /cancel — скасувати.")
+	}, th.CommandEqual("login"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		loginWizardMu.Lock()
+		_, login := loginWizard[message.From.ID]
+		delete(loginWizard, message.From.ID)
+		loginWizardMu.Unlock()
+
+		newWizardMu.Lock()
+		_, wizard := newWizard[message.From.ID]
+		delete(newWizard, message.From.ID)
+		newWizardMu.Unlock()
+
+		if !login && !wizard {
+			return sendText(ctx, message.Chat.ID, "Нічого скасовувати")
+		}
+
+		return sendText(ctx, message.Chat.ID, "Скасовано")
+	}, th.CommandEqual("cancel"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		link, ok := store.Get(message.From.ID)
+		if !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Привʼязаний користувач Taiga: %s (%d)", link.TaigaUserName, link.TaigaUserID))
+	}, th.CommandEqual("me"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		projects, err := client.ListProjects(context.Background())
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список проєктів: %s", describeTaigaError(err)))
+		}
+
+		if len(projects) == 0 {
+			return sendText(ctx, message.Chat.ID, "Немає проєктів")
+		}
+
+		var b strings.Builder
+		for _, p := range projects {
+			b.WriteString(fmt.Sprintf("%d %s (%s)\n", p.ID, p.Name, p.Slug))
+		}
+
+		return sendText(ctx, message.Chat.ID, b.String())
+	}, th.CommandEqual("projects"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+		err := store.Delete(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося відвʼязати: %v", err))
+		}
+
+		tokenSources.Forget(message.From.ID)
+
+		return sendText(ctx, message.Chat.ID, "Відвʼязано")
+	}, th.CommandEqual("unlink"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		args := commandArgs(message.Text)
+		if args == "" {
+			bound, ok := store.GetChatProject(message.Chat.ID)
+			if !ok {
+				return sendText(ctx, message.Chat.ID, "Використання: /bindproject <project_id|slug>")
+			}
+
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Чат привʼязаний до проєкту %s (%d)", bound.Slug, bound.ProjectID))
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		admin, err := isChatAdmin(ctx, message)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося перевірити права в чаті: %v", err))
+		}
+
+		if !admin {
+			return sendText(ctx, message.Chat.ID, "Привʼязувати чат до проєкту можуть лише адміністратори чату")
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		project, err := client.FindProject(context.Background(), args)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося знайти проєкт: %s", describeTaigaError(err)))
+		}
+
+		if err := store.SetChatProject(message.Chat.ID, storage.ChatProject{ProjectID: project.ID, Slug: project.Slug}); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося привʼязати чат: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Чат привʼязано до проєкту %s (%d). Тепер можна писати просто #<ref>.", project.Name, project.ID))
+	}, th.CommandEqual("bindproject"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		admin, err := isChatAdmin(ctx, message)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося перевірити права в чаті: %v", err))
+		}
+
+		if !admin {
+			return sendText(ctx, message.Chat.ID, "Відвʼязувати чат від проєкту можуть лише адміністратори чату")
+		}
+
+		if err := store.ClearChatProject(message.Chat.ID); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося відвʼязати чат: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, "Чат відвʼязано від проєкту")
+	}, th.CommandEqual("unbindproject"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		chatID := message.Chat.ID
+		err := store.SetNotifyChat(message.From.ID, &chatID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося встановити чат для сповіщень: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Сповіщення надсилатимуться сюди (%d)", message.Chat.ID))
+	}, th.CommandEqual("notifyhere"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		chatID, err := parseChatID(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		if err := store.SetNotifyChat(message.From.ID, &chatID); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося встановити чат для сповіщень: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Сповіщення надсилатимуться в чат %d", chatID))
+	}, th.CommandEqual("notifychat"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+		err := store.SetNotifyChat(message.From.ID, nil)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося встановити приватні сповіщення: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, "Сповіщення надсилатимуться в приватний чат")
+	}, th.CommandEqual("notifypm"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, err := parseRequiredProjectID(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		// Webhook events of a watched project reach the watcher, so only projects they can read are accepted.
+		if _, err := client.GetProject(context.Background(), projectID); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося підписатися: %s", describeTaigaError(err)))
+		}
+
+		if err := store.AddWatchedProject(message.From.ID, projectID); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося підписатися: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Підписано на проєкт %d", projectID))
+	}, th.CommandEqual("watch"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, err := parseRequiredProjectID(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		if err := store.RemoveWatchedProject(message.From.ID, projectID); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося відписатися: %v", err))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Відписано від проєкту %d", projectID))
+	}, th.CommandEqual("unwatch"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		link, ok := store.Get(message.From.ID)
+		if !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		if len(link.WatchedProjects) == 0 {
+			return sendText(ctx, message.Chat.ID, "Немає проєктів у підписках")
+		}
+
+		var b strings.Builder
+		b.WriteString("Підписки на проєкти:\n")
+
+		for _, pid := range link.WatchedProjects {
+			b.WriteString(fmt.Sprintf("%d\n", pid))
+		}
+
+		return sendText(ctx, message.Chat.ID, b.String())
+	}, th.CommandEqual("watches"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, assigneeID, subject, description, err := parseTaskTo(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		req := taiga.UserStoryCreateRequest{
+			ProjectID:   projectID,
+			Subject:     subject,
+			Description: description,
+			Assigned:    &assigneeID,
+		}
+
+		us, err := client.CreateUserStory(context.Background(), req)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити завдання: %s", describeTaigaError(err)))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s", us.Ref, us.Subject))
+	}, th.CommandEqual("taskto"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		link, ok := store.Get(message.From.ID)
+		if !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, assigneeID, subject, description, err := parseTaskWithOptionalAssignee(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		if assigneeID == nil {
+			assigneeID = &link.TaigaUserID
+		}
+
+		req := taiga.UserStoryCreateRequest{
+			ProjectID:   projectID,
+			Subject:     subject,
+			Description: description,
+			Assigned:    assigneeID,
+		}
+
+		us, err := client.CreateUserStory(context.Background(), req)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити завдання: %s", describeTaigaError(err)))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено завдання #%d: %s", us.Ref, us.Subject))
+	}, th.CommandEqual("task"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, subject, description, err := parseIssue(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		req := taiga.IssueCreateRequest{
+			ProjectID:   projectID,
+			Subject:     subject,
+			Description: description,
+		}
+
+		issue, err := client.CreateIssue(context.Background(), req)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося створити запит: %s", describeTaigaError(err)))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Створено запит #%d: %s", issue.Ref, issue.Subject))
+	}, th.CommandEqual("issue"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, err := parseRequiredProjectID(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		epics, err := client.ListEpics(context.Background(), taiga.ListEpicsParams{ProjectID: projectID})
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список епіків: %s", describeTaigaError(err)))
+		}
+
+		if len(epics) == 0 {
+			return sendText(ctx, message.Chat.ID, "Немає епіків")
+		}
+
+		progress, progressErr := client.ListEpicProgress(context.Background(), projectID)
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("Епіки проєкту %d:\n", projectID))
+
+		for _, epic := range epics {
+			if progressErr != nil {
+				b.WriteString(fmt.Sprintf("#%d %s [%s] (прогрес недоступний)\n", epic.Ref, epic.Subject, epic.StatusExtraInfo.Name))
+				continue
+			}
+
+			progress := progress[epic.ID]
+			b.WriteString(fmt.Sprintf("#%d %s [%s] %d/%d\n", epic.Ref, epic.Subject, epic.StatusExtraInfo.Name, progress.Closed, progress.Total))
+		}
+
+		return sendText(ctx, message.Chat.ID, b.String())
+	}, th.CommandEqual("epics"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, err := parseRequiredProjectID(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		milestone, err := findActiveMilestone(context.Background(), client, projectID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		milestone, err = client.GetMilestone(context.Background(), milestone.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати спринт: %s", describeTaigaError(err)))
+		}
+
+		stats, err := client.GetMilestoneStats(context.Background(), milestone.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати статистику спринту: %s", describeTaigaError(err)))
+		}
+
+		return sendText(ctx, message.Chat.ID, formatSprint(milestone, stats, time.Now()))
+	}, th.CommandEqual("sprint"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		if _, ok := store.Get(message.From.ID); !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		ref, err := parseProjectRef(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		us, err := resolveItem(client, message.Chat.ID, ref)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		if us.Kind != taiga.KindUserStory {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("#%d не є завданням (user story)", us.Ref))
+		}
+
+		milestone, err := findActiveMilestone(context.Background(), client, us.Project)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		if err := client.MoveUserStoriesToMilestone(context.Background(), us.Project, milestone.ID, []int64{us.ID}); err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося перенести завдання: %s", describeTaigaError(err)))
+		}
+
+		return sendText(ctx, message.Chat.ID, fmt.Sprintf("Завдання #%d %s перенесено в спринт %s", us.Ref, us.Subject, milestone.Name))
+	}, th.CommandEqual("tosprint"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		link, ok := store.Get(message.From.ID)
+		if !ok {
+			return sendText(ctx, message.Chat.ID, "Немає привʼязки. Використай /link <auth_token> <refresh_token>.")
+		}
+
+		projectID, err := parseOptionalProjectID(commandArgs(message.Text))
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		assigned := link.TaigaUserID
+		stories, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{ProjectID: projectID, AssignedTo: &assigned})
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список завдання: %s", describeTaigaError(err)))
+		}
+
+		issues, err := client.ListIssues(context.Background(), taiga.ListIssuesParams{ProjectID: projectID, AssignedTo: &assigned})
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список запитів: %s", describeTaigaError(err)))
+		}
+
+		if len(stories) == 0 && len(issues) == 0 {
+			return sendText(ctx, message.Chat.ID, "Немає завдання")
+		}
+
+		var b strings.Builder
+		for _, us := range stories {
+			b.WriteString(fmt.Sprintf("#%d %s [%s]\n", us.Ref, us.Subject, us.StatusExtraInfo.Name))
+		}
+
+		if len(issues) > 0 {
+			if len(stories) > 0 {
+				b.WriteString("\n")
+			}
+
+			b.WriteString("Запити:\n")
+
+			for _, issue := range issues {
+				b.WriteString(fmt.Sprintf("#%d %s [%s]\n", issue.Ref, issue.Subject, issue.StatusExtraInfo.Name))
+			}
+		}
+
+		return sendText(ctx, message.Chat.ID, b.String())
+	}, th.CommandEqual("my"))
+
+	bh.HandleMessage(func(ctx *th.Context, message telego.Message) error {
+		if message.From == nil {
+			return sendText(ctx, message.Chat.ID, "Відсутня інформація про користувача")
+		}
+
+		args := strings.TrimSpace(commandArgs(message.Text))
+
+		parts := strings.Fields(args)
+		if len(parts) != 2 {
+			return sendText(ctx, message.Chat.ID, "Використання: /myfor <project_id> <telegram_user_id|@username>")
+		}
+
+		projectID, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil || projectID <= 0 {
+			return sendText(ctx, message.Chat.ID, "Некоректний id проєкту")
+		}
+
+		targetTelegramID, err := resolveTelegramTarget(parts[1])
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, err.Error())
+		}
+
+		admin, err := isProjectAdmin(ctx, message.From.ID, projectID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка перевірки прав: %s", describeTaigaError(err)))
+		}
+
+		if !admin {
+			return sendText(ctx, message.Chat.ID, "Недостатньо прав: потрібен адміністратор проєкту в Taiga")
+		}
+
+		client, err := newTaigaClient(message.From.ID)
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Помилка клієнта Taiga: %s", describeTaigaError(err)))
+		}
+
+		taigaUserID, ok := store.GetProjectUserMapping(projectID, targetTelegramID)
+		if !ok {
+			if targetLink, ok2 := store.Get(targetTelegramID); ok2 {
+				taigaUserID = targetLink.TaigaUserID
+				ok = true
+			}
+		}
+
+		if !ok || taigaUserID <= 0 {
+			return sendText(ctx, message.Chat.ID, "Немає мапінгу/привʼязки для цього користувача")
+		}
+
+		assigned := taigaUserID
+
+		stories, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{ProjectID: projectID, AssignedTo: &assigned})
+		if err != nil {
+			return sendText(ctx, message.Chat.ID, fmt.Sprintf("Не вдалося отримати список завдання: %s", describeTaigaError(err)))
+		}
+
+		if len(stories) == 0 {
+			return sendText(ctx, message.Chat.ID, "Немає завдання")
+		}
+
+		var b strings.Builder
+		for _, us := range stories {
+			b.WriteString(fmt.Sprintf("#%d %s [%s]\n", us.Ref, us.Subject, us.StatusExtraInfo.Name))
+		}
+
+		return sendText(ctx, message.Chat.ID, b.String())
+	}, th.CommandEqual("myfor"))
+
+	skipPolling := func(int64) bool { return false }
+
+	if cfg.WebhookAddr != "" {
+		hooks := webhook.NewHandler(cfg.WebhookSecrets, cfg.WebhookWindow, func(ctx context.Context, projectID int64, event webhook.Event) {
+			handleWebhookEvent(ctx, bot, store, tokenSources, cfg.TaigaBaseURL, projectID, event)
+		})
+
+		mux := http.NewServeMux()
+		hooks.Register(mux)
+
+		server := &http.Server{Addr: cfg.WebhookAddr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+		// Binding up front fails startup instead of silently leaving polling disabled without webhooks.
+		listener, err := net.Listen("tcp", cfg.WebhookAddr)
+		if err != nil {
+			log.Fatalf("webhook server: %v", err)
+		}
+
+		go hooks.Run(ctx)
+
+		go func() {
+			if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("webhook server: %v", err)
+			}
+		}()
+
+		go func() {
+			<-ctx.Done()
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_ = server.Shutdown(shutdownCtx)
+		}()
+
+		skipPolling = hooks.Active
+	}
+
+	go pollNotifications(ctx, bot, store, tokenSources, cfg.TaigaBaseURL, cfg.PollInterval, skipPolling)
+	go dailyAssignedDigest(ctx, bot, store, tokenSources, cfg.TaigaBaseURL)
+
+	if err := bh.Start(); err != nil {
+		log.Fatalf("start handler: %v", err)
+	}
+}
+
+// openStore opens the link store with the configured backend, encrypting tokens when a key is set.
+func openStore(cfg config.Config) (*storage.Store, error) {
+	var backend storage.Backend = storage.NewFileBackend(cfg.StoragePath)
+	if cfg.StorageBackend == config.StorageBackendJournal {
+		backend = storage.NewJournalBackend(cfg.StoragePath)
+	}
+
+	if cfg.TokenKey != nil {
+		tokenCipher, err := storage.NewTokenCipher(cfg.TokenKey, cfg.PreviousTokenKeys...)
+		if err != nil {
+			return nil, err
+		}
+
+		backend = storage.NewEncryptedBackend(backend, tokenCipher)
+	}
+
+	return storage.Open(backend)
+}
+
+// rotateTokenKey implements `taigagra rotate-token-key`: it re-encrypts every stored token
+// with TOKEN_ENCRYPTION_KEY, reading tokens sealed with TOKEN_ENCRYPTION_PREVIOUS_KEYS.
+// Afterwards the previous keys can be dropped.
+func rotateTokenKey() {
+	cfg, err := config.LoadStorage()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+
+	if cfg.TokenKey == nil {
+		log.Fatalf("rotate-token-key: TOKEN_ENCRYPTION_KEY or TOKEN_ENCRYPTION_KEY_FILE is required")
+	}
+
+	store, err := openStore(cfg)
+	if err != nil {
+		log.Fatalf("open storage: %v", err)
+	}
+
+	if err := store.Rewrite(); err != nil {
+		log.Fatalf("re-encrypt storage: %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		log.Fatalf("close storage: %v", err)
+	}
+
+	log.Printf("re-encrypted the tokens of %d users", len(store.List()))
+}
+
+// maxTelegramFileSize matches the Bot API download limit.
+const maxTelegramFileSize = 20 * 1024 * 1024
+
+// attachmentFile picks the file to upload from a message: the document, or the largest photo size.
+func attachmentFile(message telego.Message) (fileID, fileName string) {
+	if message.Document != nil {
+		fileName = message.Document.FileName
+		if fileName == "" {
+			fileName = message.Document.FileUniqueID
+		}
+
+		return message.Document.FileID, fileName
+	}
+
+	if len(message.Photo) == 0 {
+		return "", ""
+	}
+
+	largest := message.Photo[0]
+	for _, p := range message.Photo[1:] {
+		if p.Width*p.Height > largest.Width*largest.Height {
+			largest = p
+		}
+	}
+
+	return largest.FileID, fmt.Sprintf("photo_%s.jpg", largest.FileUniqueID)
+}
+
+// downloadTelegramFile fetches file contents through the Telegram file API.
+func downloadTelegramFile(ctx context.Context, bot *telego.Bot, fileID string) ([]byte, error) {
+	if fileID == "" {
+		return nil, errors.New("немає файлу")
+	}
+
+	file, err := bot.GetFile(ctx, &telego.GetFileParams{FileID: fileID})
+	if err != nil {
+		return nil, err
+	}
+
+	if file.FileSize > maxTelegramFileSize {
+		return nil, fmt.Errorf("файл завеликий: %d байт", file.FileSize)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, bot.FileDownloadURL(file.FilePath), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("telegram повернув статус %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxTelegramFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(content) > maxTelegramFileSize {
+		return nil, errors.New("файл завеликий")
+	}
+
+	return content, nil
+}
+
+func sendText(ctx *th.Context, chatID int64, text string) error {
+	if text == "" {
+		return nil
+	}
+
+	for _, chunk := range splitMessage(text, 3500) {
+		_, err := ctx.Bot().SendMessage(ctx, tu.Message(tu.ID(chatID), chunk))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
 func sendTextBot(ctx context.Context, bot *telego.Bot, chatID int64, text string) {
 	if text == "" {
@@ -1045,210 +2287,999 @@ func sendTextBot(ctx context.Context, bot *telego.Bot, chatID int64, text string
 	}
 }
 
-func splitMessage(text string, limit int) []string {
-	if limit <= 0 {
-		return []string{text}
+func splitMessage(text string, limit int) []string {
+	if limit <= 0 {
+		return []string{text}
+	}
+
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return []string{text}
+	}
+
+	parts := make([]string, 0, (len(runes)/limit)+1)
+	for len(runes) > 0 {
+		if len(runes) <= limit {
+			parts = append(parts, strings.TrimSpace(string(runes)))
+			break
+		}
+
+		window := runes[:limit]
+
+		cut := len(window)
+		for i := len(window) - 1; i >= 0; i-- {
+			if window[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+
+		chunk := strings.TrimSpace(string(runes[:cut]))
+		if chunk != "" {
+			parts = append(parts, chunk)
+		}
+
+		runes = runes[cut:]
+	}
+
+	return parts
+}
+
+func commandArgs(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	parts := strings.SplitN(text, " ", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[1]
+}
+
+func parseOptionalProjectID(raw string) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+
+	projectID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.New("некоректний id проєкту")
+	}
+
+	return projectID, nil
+}
+
+func parseChatID(raw string) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, errors.New("потрібен id чату")
+	}
+
+	chatID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.New("некоректний id чату")
+	}
+
+	return chatID, nil
+}
+
+func parseTaskWithOptionalAssignee(raw string) (projectID int64, assigneeID *int64, subject, description string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil, "", "", errors.New("Використання: /task <project_id> [taiga_user_id] <subject> [| description]")
+	}
+
+	fields := strings.Fields(raw)
+	if len(fields) < 2 {
+		return 0, nil, "", "", errors.New("Використання: /task <project_id> [taiga_user_id] <subject> [| description]")
+	}
+
+	projectID, err = strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, nil, "", "", errors.New("некоректний id проєкту")
+	}
+
+	remaining := strings.TrimSpace(strings.TrimPrefix(raw, fields[0]))
+
+	if len(fields) >= 3 {
+		candidate, parseErr := strconv.ParseInt(fields[1], 10, 64)
+		if parseErr == nil {
+			assigneeID = &candidate
+			remaining = strings.TrimSpace(strings.TrimPrefix(remaining, fields[1]))
+		}
+	}
+
+	subject, description = splitSubjectDescription(strings.TrimSpace(remaining))
+	if subject == "" {
+		return 0, nil, "", "", errors.New("потрібна тема")
+	}
+
+	return projectID, assigneeID, subject, description, nil
+}
+
+func parseRequiredProjectID(raw string) (int64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, errors.New("потрібен id проєкту")
+	}
+
+	projectID, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.New("некоректний id проєкту")
+	}
+
+	if projectID <= 0 {
+		return 0, errors.New("некоректний id проєкту")
+	}
+
+	return projectID, nil
+}
+
+func parseTask(raw string) (projectID int64, subject, description string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, "", "", errors.New("Використання: /task <project_id> <subject> [| description]")
+	}
+
+	parts := strings.SplitN(raw, " ", 2)
+	if len(parts) < 2 {
+		return 0, "", "", errors.New("Використання: /task <project_id> <subject> [| description]")
+	}
+
+	projectID, err = strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil {
+		return 0, "", "", errors.New("некоректний id проєкту")
+	}
+
+	subject, description = splitSubjectDescription(strings.TrimSpace(parts[1]))
+	if subject == "" {
+		return 0, "", "", errors.New("потрібна тема")
+	}
+
+	return projectID, subject, description, nil
+}
+
+func parseIssue(raw string) (projectID int64, subject, description string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, "", "", errors.New("Використання: /issue <project_id> <subject> [| description]")
+	}
+
+	parts := strings.SplitN(raw, " ", 2)
+	if len(parts) < 2 {
+		return 0, "", "", errors.New("Використання: /issue <project_id> <subject> [| description]")
+	}
+
+	projectID, err = strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	if err != nil || projectID <= 0 {
+		return 0, "", "", errors.New("некоректний id проєкту")
+	}
+
+	subject, description = splitSubjectDescription(strings.TrimSpace(parts[1]))
+	if subject == "" {
+		return 0, "", "", errors.New("потрібна тема")
+	}
+
+	return projectID, subject, description, nil
+}
+
+func parseTaskTo(raw string) (projectID, assigneeID int64, subject, description string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, 0, "", "", errors.New("Використання: /taskto <project_id> <taiga_user_id> <subject> [| description]")
+	}
+
+	parts := strings.Fields(raw)
+	if len(parts) < 3 {
+		return 0, 0, "", "", errors.New("Використання: /taskto <project_id> <taiga_user_id> <subject> [| description]")
+	}
+
+	projectID, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, "", "", errors.New("некоректний id проєкту")
+	}
+
+	assigneeID, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, "", "", errors.New("некоректний id користувача Taiga")
+	}
+
+	rest := strings.TrimSpace(strings.TrimPrefix(raw, parts[0]))
+
+	rest = strings.TrimSpace(strings.TrimPrefix(rest, parts[1]))
+
+	subject, description = splitSubjectDescription(strings.TrimSpace(rest))
+	if subject == "" {
+		return 0, 0, "", "", errors.New("потрібна тема")
+	}
+
+	return projectID, assigneeID, subject, description, nil
+}
+
+// parseProjectRef parses "/tosprint" arguments: an item ref, or the older "<project> <ref>" form.
+func parseProjectRef(raw string) (itemRef, error) {
+	fields := strings.Fields(raw)
+
+	switch len(fields) {
+	case 1:
+		return parseItemRef(fields[0])
+	case 2:
+		return parseItemRef(fields[0] + "#" + strings.TrimPrefix(fields[1], "#"))
+	default:
+		return itemRef{}, errors.New("Використання: /tosprint <project>#<ref>")
+	}
+}
+
+// itemRef is a work item reference typed by a user.
+// An empty Project means the project bound to the chat.
+type itemRef struct {
+	Project string
+	Ref     int64
+}
+
+// parseItemRef parses "<project>#<ref>" where project is a slug or numeric id, or a bare "#<ref>".
+func parseItemRef(raw string) (itemRef, error) {
+	raw = strings.TrimSpace(raw)
+
+	projectRaw, refRaw, ok := strings.Cut(raw, "#")
+	if !ok {
+		return itemRef{}, errors.New("очікується <project>#<ref> або #<ref>")
+	}
+
+	if strings.ContainsFunc(projectRaw, unicode.IsSpace) {
+		return itemRef{}, errors.New("некоректний проєкт")
+	}
+
+	ref, err := strconv.ParseInt(refRaw, 10, 64)
+	if err != nil || ref <= 0 {
+		return itemRef{}, errors.New("некоректний номер")
+	}
+
+	return itemRef{Project: projectRaw, Ref: ref}, nil
+}
+
+// splitFirstField returns the first whitespace separated field and the trimmed remainder.
+func splitFirstField(raw string) (first, rest string) {
+	raw = strings.TrimSpace(raw)
+
+	idx := strings.IndexFunc(raw, unicode.IsSpace)
+	if idx < 0 {
+		return raw, ""
+	}
+
+	return raw[:idx], strings.TrimSpace(raw[idx:])
+}
+
+func splitSubjectDescription(raw string) (subject, description string) {
+	if raw == "" {
+		return "", ""
+	}
+
+	parts := strings.SplitN(raw, "|", 2)
+
+	subject = strings.TrimSpace(parts[0])
+	if len(parts) == 2 {
+		description = strings.TrimSpace(parts[1])
+	}
+
+	return subject, description
+}
+
+// describeTaigaError explains typed Taiga failures in user terms; other errors are shown as is.
+func describeTaigaError(err error) string {
+	switch {
+	case errors.Is(err, taiga.ErrRefreshRejected):
+		return "термін дії refresh токена минув або Taiga його відкликала. Привʼяжи акаунт знову: /link <auth_token> <refresh_token>"
+	case errors.Is(err, taiga.ErrUnauthorized):
+		return "Taiga не приймає токен. Привʼяжи акаунт знову: /link <auth_token> <refresh_token>"
+	case errors.Is(err, taiga.ErrForbidden):
+		return "недостатньо прав у Taiga"
+	case errors.Is(err, taiga.ErrNotFound):
+		return "не знайдено в Taiga"
+	case errors.Is(err, taiga.ErrRateLimited):
+		return "Taiga перевантажена запитами, спробуй пізніше"
 	}
 
-	runes := []rune(text)
-	if len(runes) <= limit {
-		return []string{text}
+	var apiErr *taiga.APIError
+	if errors.As(err, &apiErr) && errors.Is(err, taiga.ErrValidation) {
+		return "Taiga відхилила дані: " + apiErr.Reason()
 	}
 
-	parts := make([]string, 0, (len(runes)/limit)+1)
-	for len(runes) > 0 {
-		if len(runes) <= limit {
-			parts = append(parts, strings.TrimSpace(string(runes)))
-			break
+	return err.Error()
+}
+
+// newUserClient returns a client on the user's shared token source. Refreshed tokens
+// are written back to the store without touching the rest of the link.
+func newUserClient(taigaBaseURL string, store *storage.Store, tokens *taiga.TokenSources, link storage.UserLink) (*taiga.Client, error) {
+	telegramID := link.TelegramID
+	source := tokens.Get(telegramID, func() *taiga.TokenSource {
+		if link.TaigaTokenType == storage.TokenTypeApplication {
+			return taiga.NewApplicationTokenSource(link.TaigaToken)
 		}
 
-		window := runes[:limit]
+		// onRefresh calls never overlap, so previous always holds the refresh token being replaced.
+		previous := link.TaigaRefresh
 
-		cut := len(window)
-		for i := len(window) - 1; i >= 0; i-- {
-			if window[i] == '\n' {
-				cut = i + 1
-				break
+		return taiga.NewTokenSource(link.TaigaToken, link.TaigaRefresh, func(authToken, refreshToken string) {
+			if err := store.UpdateTokens(telegramID, previous, authToken, refreshToken); err != nil {
+				log.Printf("save refreshed tokens: telegram_id=%d err=%v", telegramID, err)
+				return
 			}
-		}
 
-		chunk := strings.TrimSpace(string(runes[:cut]))
-		if chunk != "" {
-			parts = append(parts, chunk)
+			previous = refreshToken
+		})
+	})
+
+	return taiga.NewClientWithTokenSource(taigaBaseURL, source)
+}
+
+// isChatAdmin reports whether the sender of message may change chat-wide settings:
+// anyone in a private chat, only the creator and administrators in groups and channels.
+func isChatAdmin(ctx *th.Context, message telego.Message) (bool, error) {
+	if message.Chat.Type == "private" {
+		return true, nil
+	}
+
+	if message.From == nil {
+		return false, nil
+	}
+
+	member, err := ctx.Bot().GetChatMember(ctx, &telego.GetChatMemberParams{ChatID: tu.ID(message.Chat.ID), UserID: message.From.ID})
+	if err != nil {
+		return false, err
+	}
+
+	switch member.MemberStatus() {
+	case telego.MemberStatusCreator, telego.MemberStatusAdministrator:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// scrubSecretMessage deletes a group message that carries Taiga tokens and warns its sender.
+func scrubSecretMessage(ctx *th.Context, message telego.Message) {
+	sender := "Увага"
+	if message.From != nil {
+		sender = message.From.FirstName
+		if message.From.Username != "" {
+			sender = "@" + message.From.Username
 		}
+	}
 
-		runes = runes[cut:]
+	err := ctx.Bot().DeleteMessage(ctx, &telego.DeleteMessageParams{ChatID: tu.ID(message.Chat.ID), MessageID: message.MessageID})
+	if err != nil {
+		log.Printf("delete secret message: chat_id=%d message_id=%d err=%v", message.Chat.ID, message.MessageID, err)
+		_ = sendText(ctx, message.Chat.ID, sender+", не надсилай токени Taiga в групові чати. Я не можу видалити повідомлення — видали його сам і вийди з Taiga на всіх пристроях, щоб відкликати токени.")
+
+		return
 	}
 
-	return parts
+	_ = sendText(ctx, message.Chat.ID, sender+", не надсилай токени Taiga в групові чати. Повідомлення видалено.")
 }
 
-func commandArgs(text string) string {
-	if text == "" {
-		return ""
+// issueLinkNonce returns a one-time code that lets telegramID continue linking in private chat.
+func issueLinkNonce(telegramID int64, now time.Time) string {
+	linkNoncesMu.Lock()
+	defer linkNoncesMu.Unlock()
+
+	for nonce, entry := range linkNonces {
+		if now.After(entry.Expires) {
+			delete(linkNonces, nonce)
+		}
 	}
 
-	parts := strings.SplitN(text, " ", 2)
-	if len(parts) < 2 {
-		return ""
+	nonce := rand.Text()
+	linkNonces[nonce] = linkNonce{TelegramID: telegramID, Expires: now.Add(linkNonceTTL)}
+
+	return nonce
+}
+
+// consumeLinkNonce reports whether nonce was issued to telegramID and is still valid. A nonce works once.
+func consumeLinkNonce(nonce string, telegramID int64, now time.Time) bool {
+	linkNoncesMu.Lock()
+	defer linkNoncesMu.Unlock()
+
+	entry, ok := linkNonces[nonce]
+	if !ok || entry.TelegramID != telegramID {
+		return false
 	}
 
-	return parts[1]
+	delete(linkNonces, nonce)
+
+	return !now.After(entry.Expires)
 }
 
-func parseOptionalProjectID(raw string) (int64, error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return 0, nil
+// loginLink signs a user in with their Taiga credentials and builds the link to store.
+// With an application configured the link holds an application token, otherwise the
+// auth and refresh tokens of the session; the password itself is never stored.
+func loginLink(ctx context.Context, cfg config.Config, telegramID int64, username, password string) (storage.UserLink, error) {
+	anonymous, err := taiga.NewClient(cfg.TaigaBaseURL, "")
+	if err != nil {
+		return storage.UserLink{}, err
 	}
 
-	projectID, err := strconv.ParseInt(raw, 10, 64)
+	auth, err := anonymous.Login(ctx, username, password)
 	if err != nil {
-		return 0, errors.New("некоректний id проєкту")
+		return storage.UserLink{}, err
 	}
 
-	return projectID, nil
-}
+	link := storage.UserLink{
+		TelegramID:    telegramID,
+		TaigaToken:    auth.AuthToken,
+		TaigaRefresh:  auth.Refresh,
+		TaigaUserID:   auth.ID,
+		TaigaUserName: auth.FullName,
+	}
 
-func parseChatID(raw string) (int64, error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return 0, errors.New("потрібен id чату")
+	if cfg.TaigaAppID == "" {
+		return link, nil
 	}
 
-	chatID, err := strconv.ParseInt(raw, 10, 64)
+	client, err := taiga.NewClientWithTokens(cfg.TaigaBaseURL, auth.AuthToken, auth.Refresh, nil)
 	if err != nil {
-		return 0, errors.New("некоректний id чату")
+		return storage.UserLink{}, err
 	}
 
-	return chatID, nil
+	state := rand.Text()
+
+	code, err := client.AuthorizeApplication(ctx, cfg.TaigaAppID, state)
+	if err != nil {
+		return storage.UserLink{}, fmt.Errorf("не вдалося авторизувати застосунок: %w", err)
+	}
+
+	token, err := client.ValidateApplication(ctx, cfg.TaigaAppID, cfg.TaigaAppKey, code, state)
+	if err != nil {
+		return storage.UserLink{}, fmt.Errorf("не вдалося отримати токен застосунку: %w", err)
+	}
+
+	link.TaigaToken = token
+	link.TaigaRefresh = ""
+	link.TaigaTokenType = storage.TokenTypeApplication
+
+	return link, nil
 }
 
-func parseTaskWithOptionalAssignee(raw string) (projectID int64, assigneeID *int64, subject, description string, err error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return 0, nil, "", "", errors.New("Використання: /task <project_id> [taiga_user_id] <subject> [| description]")
+// refreshExpiryWarning is how long before the refresh token expires the user is asked to link again.
+const refreshExpiryWarning = 72 * time.Hour
+
+// tokensRejected reports whether err means the user's tokens can no longer be used or refreshed.
+func tokensRejected(err error) bool {
+	return errors.Is(err, taiga.ErrUnauthorized) || errors.Is(err, taiga.ErrRefreshRejected)
+}
+
+// warnRefreshExpiry tells the user once that their refresh token expires soon, while polling still works.
+func warnRefreshExpiry(ctx context.Context, bot *telego.Bot, store *storage.Store, link storage.UserLink, chatID int64, now time.Time) {
+	if link.RefreshExpiryWarned {
+		return
 	}
 
-	fields := strings.Fields(raw)
-	if len(fields) < 2 {
-		return 0, nil, "", "", errors.New("Використання: /task <project_id> [taiga_user_id] <subject> [| description]")
+	expiry, ok := taiga.TokenExpiry(link.TaigaRefresh)
+	if !ok || expiry.Sub(now) > refreshExpiryWarning {
+		return
 	}
 
-	projectID, err = strconv.ParseInt(fields[0], 10, 64)
-	if err != nil {
-		return 0, nil, "", "", errors.New("некоректний id проєкту")
+	if err := store.SetRefreshExpiryWarned(link.TelegramID, true); err != nil {
+		log.Printf("refresh expiry warning: telegram_id=%d err=%v", link.TelegramID, err)
+		return
 	}
 
-	remaining := strings.TrimSpace(strings.TrimPrefix(raw, fields[0]))
+	log.Printf("refresh token expires soon: telegram_id=%d expires=%s", link.TelegramID, expiry.Format(time.RFC3339))
+	sendTextBot(ctx, bot, chatID, fmt.Sprintf("Refresh токен Taiga діє до %s. Після цього сповіщення зупиняться — привʼяжи акаунт знову: /link <auth_token> <refresh_token>", expiry.Local().Format("02.01.2006 15:04")))
+}
 
-	if len(fields) >= 3 {
-		candidate, parseErr := strconv.ParseInt(fields[1], 10, 64)
-		if parseErr == nil {
-			assigneeID = &candidate
-			remaining = strings.TrimSpace(strings.TrimPrefix(remaining, fields[1]))
+// disablePolling stops background requests for a user whose Taiga tokens were rejected and tells them how to recover.
+func disablePolling(ctx context.Context, bot *telego.Bot, store *storage.Store, telegramID, chatID int64) {
+	if err := store.SetPollingDisabled(telegramID, true); err != nil {
+		log.Printf("disable polling: telegram_id=%d err=%v", telegramID, err)
+		return
+	}
+
+	log.Printf("polling disabled: telegram_id=%d reason=unauthorized", telegramID)
+	sendTextBot(ctx, bot, chatID, "Taiga більше не приймає твій токен, тому сповіщення призупинено. Привʼяжи акаунт знову: /link <auth_token> <refresh_token>")
+}
+
+// setItemStatus moves a user story, task, issue or epic to another status, retrying once on a version conflict.
+func setItemStatus(ctx context.Context, client *taiga.Client, kind taiga.ItemKind, id, statusID int64) error {
+	opts := taiga.UpdateOptions{RetryOnConflict: true}
+
+	var err error
+
+	switch kind {
+	case taiga.KindUserStory:
+		_, err = client.UpdateUserStory(ctx, id, taiga.UserStoryUpdateRequest{StatusID: &statusID}, opts)
+	case taiga.KindTask:
+		_, err = client.UpdateTask(ctx, id, taiga.TaskUpdateRequest{StatusID: &statusID}, opts)
+	case taiga.KindIssue:
+		_, err = client.UpdateIssue(ctx, id, taiga.IssueUpdateRequest{StatusID: &statusID}, opts)
+	case taiga.KindEpic:
+		_, err = client.UpdateEpic(ctx, id, taiga.EpicUpdateRequest{StatusID: &statusID}, opts)
+	default:
+		err = fmt.Errorf("невідомий тип обʼєкта: %s", kind)
+	}
+
+	return err
+}
+
+// itemCard collects what /show renders for a user story, task or issue.
+type itemCard struct {
+	DueDate     string
+	Kind        taiga.ItemKind
+	Subject     string
+	Description string
+	Status      string
+	Sprint      string
+	ProjectSlug string
+	Assignees   []string
+	Watchers    []string
+	Tags        []string
+	Owner       string
+	Points      *float64
+	Ref         int64
+}
+
+// loadItemCard fetches an item with its details and resolves user and sprint names.
+func loadItemCard(ctx context.Context, client *taiga.Client, kind taiga.ItemKind, id int64) (itemCard, error) {
+	var (
+		card        itemCard
+		assignees   []int64
+		owner       *int64
+		watchers    []int64
+		milestoneID *int64
+	)
+
+	switch kind {
+	case taiga.KindUserStory:
+		us, err := client.GetUserStory(ctx, id)
+		if err != nil {
+			return card, err
+		}
+
+		points := float64(us.TotalPoints)
+		card = itemCard{
+			Kind:        kind,
+			Ref:         us.Ref,
+			Subject:     us.Subject,
+			Description: us.Description,
+			Status:      us.StatusExtraInfo.Name,
+			Sprint:      us.MilestoneName,
+			DueDate:     us.DueDate,
+			ProjectSlug: us.ProjectExtraInfo.Slug,
+			Tags:        us.Tags,
+			Points:      &points,
+		}
+
+		assignees = us.AssignedUsers
+		if len(assignees) == 0 && us.AssignedTo != nil {
+			assignees = []int64{*us.AssignedTo}
+		}
+
+		owner, watchers, milestoneID = us.Owner, us.Watchers, us.Milestone
+
+	case taiga.KindTask:
+		task, err := client.GetTask(ctx, id)
+		if err != nil {
+			return card, err
+		}
+
+		card = itemCard{
+			Kind:        kind,
+			Ref:         task.Ref,
+			Subject:     task.Subject,
+			Description: task.Description,
+			Status:      task.StatusExtraInfo.Name,
+			DueDate:     task.DueDate,
+			ProjectSlug: task.ProjectExtraInfo.Slug,
+			Tags:        task.Tags,
+		}
+
+		if task.AssignedTo != nil {
+			assignees = []int64{*task.AssignedTo}
+		}
+
+		owner, watchers, milestoneID = task.Owner, task.Watchers, task.Milestone
+
+	case taiga.KindIssue:
+		issue, err := client.GetIssue(ctx, id)
+		if err != nil {
+			return card, err
+		}
+
+		card = itemCard{
+			Kind:        kind,
+			Ref:         issue.Ref,
+			Subject:     issue.Subject,
+			Description: issue.Description,
+			Status:      issue.StatusExtraInfo.Name,
+			DueDate:     issue.DueDate,
+			ProjectSlug: issue.ProjectExtraInfo.Slug,
+			Tags:        issue.Tags,
+		}
+
+		if issue.AssignedTo != nil {
+			assignees = []int64{*issue.AssignedTo}
+		}
+
+		owner, watchers, milestoneID = issue.Owner, issue.Watchers, issue.Milestone
+
+	default:
+		return card, errors.New("картка доступна лише для завдань, задач і запитів")
+	}
+
+	if card.Sprint == "" && milestoneID != nil {
+		if milestone, err := client.GetMilestone(ctx, *milestoneID); err == nil {
+			card.Sprint = milestone.Name
 		}
 	}
 
-	subject, description = splitSubjectDescription(strings.TrimSpace(remaining))
-	if subject == "" {
-		return 0, nil, "", "", errors.New("потрібна тема")
+	names := make(map[int64]string)
+	userName := func(id int64) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+
+		name := fmt.Sprintf("#%d", id)
+		if user, err := client.GetUser(ctx, id); err == nil && user.FullName != "" {
+			name = user.FullName
+		}
+
+		names[id] = name
+
+		return name
 	}
 
-	return projectID, assigneeID, subject, description, nil
+	for _, id := range assignees {
+		card.Assignees = append(card.Assignees, userName(id))
+	}
+
+	for _, id := range watchers {
+		card.Watchers = append(card.Watchers, userName(id))
+	}
+
+	if owner != nil {
+		card.Owner = userName(*owner)
+	}
+
+	return card, nil
 }
 
-func parseRequiredProjectID(raw string) (int64, error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return 0, errors.New("потрібен id проєкту")
+// formatItemCard renders a /show card; the link is omitted when the web UI address is unknown.
+func formatItemCard(card itemCard, webURL string) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%s #%d %s\n", itemKindLabel(card.Kind), card.Ref, card.Subject))
+	b.WriteString(fmt.Sprintf("Статус: %s\n", card.Status))
+
+	if len(card.Assignees) > 0 {
+		b.WriteString(fmt.Sprintf("Виконавці: %s\n", strings.Join(card.Assignees, ", ")))
+	} else {
+		b.WriteString("Виконавці: не призначено\n")
 	}
 
-	projectID, err := strconv.ParseInt(raw, 10, 64)
+	if card.Owner != "" {
+		b.WriteString(fmt.Sprintf("Автор: %s\n", card.Owner))
+	}
+
+	if card.Points != nil {
+		b.WriteString(fmt.Sprintf("Поінти: %s\n", formatPoints(*card.Points)))
+	}
+
+	if card.Sprint != "" {
+		b.WriteString(fmt.Sprintf("Спринт: %s\n", card.Sprint))
+	}
+
+	if card.DueDate != "" {
+		b.WriteString(fmt.Sprintf("Термін: %s\n", card.DueDate))
+	}
+
+	if len(card.Tags) > 0 {
+		b.WriteString(fmt.Sprintf("Теги: %s\n", strings.Join(card.Tags, ", ")))
+	}
+
+	if len(card.Watchers) > 0 {
+		b.WriteString(fmt.Sprintf("Спостерігачі: %s\n", strings.Join(card.Watchers, ", ")))
+	}
+
+	if description := strings.TrimSpace(card.Description); description != "" {
+		b.WriteString("\n")
+		b.WriteString(description)
+		b.WriteString("\n")
+	}
+
+	if link := itemWebURL(webURL, card.ProjectSlug, card.Kind, card.Ref); link != "" {
+		b.WriteString("\n")
+		b.WriteString(link)
+	}
+
+	return b.String()
+}
+
+// inlineSearchResults searches the projects the user is a member of and turns the
+// matches into shareable cards, so results never exceed the user's own Taiga access.
+func inlineSearchResults(ctx context.Context, client *taiga.Client, taigaUserID int64, text, webURL string) ([]telego.InlineQueryResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, inlineSearchTimeout)
+	defer cancel()
+
+	projects, err := client.ListMemberProjects(ctx, taigaUserID)
 	if err != nil {
-		return 0, errors.New("некоректний id проєкту")
+		return nil, err
 	}
 
-	if projectID <= 0 {
-		return 0, errors.New("некоректний id проєкту")
+	if len(projects) > inlineSearchProjects {
+		projects = projects[:inlineSearchProjects]
 	}
 
-	return projectID, nil
+	// Projects are searched at once; results keep the order of the projects.
+	var (
+		found = make([]taiga.SearchResults, len(projects))
+		errs  = make([]error, len(projects))
+		wg    sync.WaitGroup
+	)
+
+	for i, project := range projects {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			found[i], errs[i] = client.Search(ctx, project.ID, text)
+		}()
+	}
+
+	wg.Wait()
+
+	results := make([]telego.InlineQueryResult, 0, inlineResultsLimit)
+	failed := 0
+
+	for i, project := range projects {
+		if errs[i] != nil {
+			log.Printf("inline search: project_id=%d err=%v", project.ID, errs[i])
+
+			failed++
+
+			continue
+		}
+
+		for _, matches := range [][]taiga.SearchResult{found[i].UserStories, found[i].Tasks, found[i].Issues} {
+			for _, match := range matches {
+				if len(results) == inlineResultsLimit {
+					return results, nil
+				}
+
+				results = append(results, inlineResultArticle(project, match, webURL))
+			}
+		}
+	}
+
+	if len(projects) > 0 && failed == len(projects) {
+		return nil, fmt.Errorf("пошук не вдався в жодному з %d проєктів: %w", failed, errors.Join(errs...))
+	}
+
+	return results, nil
 }
 
-func parseTask(raw string) (projectID int64, subject, description string, err error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return 0, "", "", errors.New("Використання: /task <project_id> <subject> [| description]")
+// inlineResultArticle renders one search match as an inline result sharing a short card.
+func inlineResultArticle(project taiga.Project, match taiga.SearchResult, webURL string) *telego.InlineQueryResultArticle {
+	title := fmt.Sprintf("#%d %s", match.Ref, match.Subject)
+
+	description := fmt.Sprintf("%s · %s", itemKindLabel(match.Kind), project.Name)
+	if match.StatusExtraInfo.Name != "" {
+		description += " · " + match.StatusExtraInfo.Name
 	}
 
-	parts := strings.SplitN(raw, " ", 2)
-	if len(parts) < 2 {
-		return 0, "", "", errors.New("Використання: /task <project_id> <subject> [| description]")
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("%s #%d: %s\n", itemKindLabel(match.Kind), match.Ref, match.Subject))
+	b.WriteString(fmt.Sprintf("Проєкт: %s\n", project.Name))
+
+	if match.StatusExtraInfo.Name != "" {
+		b.WriteString(fmt.Sprintf("Статус: %s\n", match.StatusExtraInfo.Name))
 	}
 
-	projectID, err = strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
-	if err != nil {
-		return 0, "", "", errors.New("некоректний id проєкту")
+	link := itemWebURL(webURL, project.Slug, match.Kind, match.Ref)
+	if link != "" {
+		b.WriteString(link)
+	}
+
+	article := tu.ResultArticle(fmt.Sprintf("%s:%d", match.Kind, match.ID), title, tu.TextMessage(strings.TrimSpace(b.String())))
+	article = article.WithDescription(description)
+
+	if link != "" {
+		article = article.WithURL(link)
+	}
+
+	return article
+}
+
+// cachedInlineResults returns unexpired inline results for a user's query.
+func cachedInlineResults(key inlineCacheKey, now time.Time) ([]telego.InlineQueryResult, bool) {
+	inlineCacheMu.Lock()
+	defer inlineCacheMu.Unlock()
+
+	entry, ok := inlineCache[key]
+	if !ok || now.After(entry.Expires) {
+		return nil, false
+	}
+
+	return entry.Results, true
+}
+
+// storeInlineResults caches inline results for inlineCacheTTL, dropping expired entries.
+func storeInlineResults(key inlineCacheKey, results []telego.InlineQueryResult, now time.Time) {
+	inlineCacheMu.Lock()
+	defer inlineCacheMu.Unlock()
+
+	for k, entry := range inlineCache {
+		if now.After(entry.Expires) {
+			delete(inlineCache, k)
+		}
+	}
+
+	inlineCache[key] = inlineCacheEntry{Results: results, Expires: now.Add(inlineCacheTTL)}
+}
+
+// searchResultsPerKind caps how many matches of each kind /find lists.
+const searchResultsPerKind = 10
+
+// formatSearchResults renders /find matches grouped by kind, with a button opening each listed item.
+func formatSearchResults(project taiga.Project, results taiga.SearchResults) (string, [][]telego.InlineKeyboardButton) {
+	var (
+		b    strings.Builder
+		rows [][]telego.InlineKeyboardButton
+		row  []telego.InlineKeyboardButton
+	)
+
+	b.WriteString(fmt.Sprintf("Знайдено в %s: %d\n", project.Name, results.Len()))
+
+	groups := []struct {
+		kind    taiga.ItemKind
+		results []taiga.SearchResult
+	}{
+		{taiga.KindUserStory, results.UserStories},
+		{taiga.KindTask, results.Tasks},
+		{taiga.KindIssue, results.Issues},
+	}
+
+	for _, group := range groups {
+		if len(group.results) == 0 {
+			continue
+		}
+
+		b.WriteString(fmt.Sprintf("\n%s (%d):\n", itemKindLabel(group.kind), len(group.results)))
+
+		for i, result := range group.results {
+			if i == searchResultsPerKind {
+				b.WriteString(fmt.Sprintf("…і ще %d\n", len(group.results)-i))
+				break
+			}
+
+			status := result.StatusExtraInfo.Name
+			if status == "" {
+				status = "?"
+			}
+
+			b.WriteString(fmt.Sprintf("#%d %s [%s]\n", result.Ref, result.Subject, status))
+
+			data := fmt.Sprintf("show:%s:%d", result.Kind, result.ID)
+
+			row = append(row, tu.InlineKeyboardButton(fmt.Sprintf("#%d", result.Ref)).WithCallbackData(data))
+			if len(row) == 5 {
+				rows = append(rows, tu.InlineKeyboardRow(row...))
+				row = nil
+			}
+		}
 	}
 
-	subject, description = splitSubjectDescription(strings.TrimSpace(parts[1]))
-	if subject == "" {
-		return 0, "", "", errors.New("потрібна тема")
+	if len(row) > 0 {
+		rows = append(rows, tu.InlineKeyboardRow(row...))
 	}
 
-	return projectID, subject, description, nil
+	return b.String(), rows
 }
 
-func parseTaskTo(raw string) (projectID, assigneeID int64, subject, description string, err error) {
-	raw = strings.TrimSpace(raw)
-	if raw == "" {
-		return 0, 0, "", "", errors.New("Використання: /taskto <project_id> <taiga_user_id> <subject> [| description]")
+func itemKindLabel(kind taiga.ItemKind) string {
+	switch kind {
+	case taiga.KindUserStory:
+		return "Завдання"
+	case taiga.KindTask:
+		return "Задача"
+	case taiga.KindIssue:
+		return "Запит"
+	case taiga.KindEpic:
+		return "Епік"
+	default:
+		return string(kind)
 	}
+}
 
-	parts := strings.Fields(raw)
-	if len(parts) < 3 {
-		return 0, 0, "", "", errors.New("Використання: /taskto <project_id> <taiga_user_id> <subject> [| description]")
+// itemWebURL builds a Taiga web UI deep link such as {web}/project/{slug}/us/{ref}.
+func itemWebURL(webURL, projectSlug string, kind taiga.ItemKind, ref int64) string {
+	if webURL == "" || projectSlug == "" {
+		return ""
 	}
 
-	projectID, err = strconv.ParseInt(parts[0], 10, 64)
-	if err != nil {
-		return 0, 0, "", "", errors.New("некоректний id проєкту")
+	var segment string
+
+	switch kind {
+	case taiga.KindUserStory:
+		segment = "us"
+	case taiga.KindTask:
+		segment = "task"
+	case taiga.KindIssue:
+		segment = "issue"
+	case taiga.KindEpic:
+		segment = "epic"
+	default:
+		return ""
 	}
 
-	assigneeID, err = strconv.ParseInt(parts[1], 10, 64)
+	return fmt.Sprintf("%s/project/%s/%s/%d", webURL, url.PathEscape(projectSlug), segment, ref)
+}
+
+func findActiveMilestone(ctx context.Context, client *taiga.Client, projectID int64) (taiga.Milestone, error) {
+	open := false
+
+	milestones, err := client.ListMilestones(ctx, taiga.ListMilestonesParams{ProjectID: projectID, Closed: &open})
 	if err != nil {
-		return 0, 0, "", "", errors.New("некоректний id користувача Taiga")
+		return taiga.Milestone{}, fmt.Errorf("не вдалося отримати список спринтів: %w", err)
 	}
 
-	rest := strings.TrimSpace(strings.TrimPrefix(raw, parts[0]))
+	milestone, ok := taiga.CurrentMilestone(milestones, time.Now())
+	if !ok {
+		return taiga.Milestone{}, errors.New("немає активного спринту")
+	}
 
-	rest = strings.TrimSpace(strings.TrimPrefix(rest, parts[1]))
+	return milestone, nil
+}
 
-	subject, description = splitSubjectDescription(strings.TrimSpace(rest))
-	if subject == "" {
-		return 0, 0, "", "", errors.New("потрібна тема")
+func formatSprint(milestone taiga.Milestone, stats taiga.MilestoneStats, now time.Time) string {
+	var b strings.Builder
+
+	b.WriteString(fmt.Sprintf("Спринт %s (%s — %s)\n", milestone.Name, milestone.EstimatedStart, milestone.EstimatedFinish))
+	b.WriteString(fmt.Sprintf("Поінти: %s/%s закрито\n", formatPoints(float64(stats.CompletedPoints)), formatPoints(float64(stats.TotalPoints))))
+	b.WriteString(fmt.Sprintf("Завдання: %d/%d закрито\n", stats.CompletedUserStories, stats.TotalUserStories))
+
+	if day, ok := stats.DayOn(now); ok {
+		b.WriteString(fmt.Sprintf("Burndown на %s: відкрито %s, за планом %s\n", day.Day, formatPoints(day.OpenPoints), formatPoints(day.OptimalPoints)))
 	}
 
-	return projectID, assigneeID, subject, description, nil
-}
+	if len(milestone.UserStories) == 0 {
+		return b.String()
+	}
 
-func splitSubjectDescription(raw string) (subject, description string) {
-	if raw == "" {
-		return "", ""
+	byStatus := make(map[string][]taiga.UserStory)
+	statuses := make([]string, 0)
+
+	for _, us := range milestone.UserStories {
+		status := us.StatusExtraInfo.Name
+		if _, ok := byStatus[status]; !ok {
+			statuses = append(statuses, status)
+		}
+
+		byStatus[status] = append(byStatus[status], us)
 	}
 
-	parts := strings.SplitN(raw, "|", 2)
+	sort.Strings(statuses)
 
-	subject = strings.TrimSpace(parts[0])
-	if len(parts) == 2 {
-		description = strings.TrimSpace(parts[1])
+	for _, status := range statuses {
+		b.WriteString(fmt.Sprintf("\n%s:\n", status))
+
+		for _, us := range byStatus[status] {
+			b.WriteString(fmt.Sprintf("#%d %s\n", us.Ref, us.Subject))
+		}
 	}
 
-	return subject, description
+	return b.String()
 }
 
-func dailyAssignedDigest(ctx context.Context, bot *telego.Bot, store *storage.Store, taigaBaseURL string) {
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+func dailyAssignedDigest(ctx context.Context, bot *telego.Bot, store *storage.Store, tokens *taiga.TokenSources, taigaBaseURL string) {
 	loc, err := time.LoadLocation("Europe/Kyiv")
 	if err != nil {
 		loc2, err2 := time.LoadLocation("Europe/Kiev")
@@ -1282,7 +3313,7 @@ func dailyAssignedDigest(ctx context.Context, bot *telego.Bot, store *storage.St
 		links := store.List()
 		log.Printf("daily digest triggered: links=%d", len(links))
 		for _, link := range links {
-			if strings.TrimSpace(link.TaigaToken) == "" || link.TaigaUserID <= 0 {
+			if strings.TrimSpace(link.TaigaToken) == "" || link.TaigaUserID <= 0 || link.PollingDisabled {
 				continue
 			}
 
@@ -1292,13 +3323,7 @@ func dailyAssignedDigest(ctx context.Context, bot *telego.Bot, store *storage.St
 			destinationChatID := *link.NotifyChatID
 			log.Printf("daily digest send: telegram_id=%d destination_chat_id=%d", link.TelegramID, destinationChatID)
 
-			linkCopy := link
-			client, err := taiga.NewClientWithTokens(taigaBaseURL, linkCopy.TaigaToken, linkCopy.TaigaRefresh, func(authToken, refreshToken string) {
-				updated := linkCopy
-				updated.TaigaToken = authToken
-				updated.TaigaRefresh = refreshToken
-				_ = store.Save(updated)
-			})
+			client, err := newUserClient(taigaBaseURL, store, tokens, link)
 			if err != nil {
 				continue
 			}
@@ -1306,8 +3331,13 @@ func dailyAssignedDigest(ctx context.Context, bot *telego.Bot, store *storage.St
 			assigned := link.TaigaUserID
 
 			stories, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{AssignedTo: &assigned})
+			if tokensRejected(err) {
+				disablePolling(ctx, bot, store, link.TelegramID, destinationChatID)
+				continue
+			}
+
 			if err != nil {
-				sendTextBot(ctx, bot, destinationChatID, fmt.Sprintf("Не вдалося отримати список завдання: %v", err))
+				sendTextBot(ctx, bot, destinationChatID, fmt.Sprintf("Не вдалося отримати список завдання: %s", describeTaigaError(err)))
 				continue
 			}
 
@@ -1328,7 +3358,451 @@ func dailyAssignedDigest(ctx context.Context, bot *telego.Bot, store *storage.St
 	}
 }
 
-func pollNotifications(ctx context.Context, bot *telego.Bot, store *storage.Store, taigaBaseURL string, interval time.Duration) {
+// trackedItem is the subset of a Taiga work item compared between polling cycles.
+type trackedItem struct {
+	AssignedTo *int64
+	Kind       taiga.ItemKind
+	Subject    string
+	Status     string
+	ID         int64
+	Ref        int64
+	ProjectID  int64
+	Version    int64
+}
+
+// itemNotification is a change message about one tracked item.
+type itemNotification struct {
+	Text string
+	Item trackedItem
+	// Updated marks a change of a known item, which its history can describe in detail.
+	Updated bool
+}
+
+// digestMessages holds the notification formats for one kind of work item.
+type digestMessages struct {
+	created         string
+	statusChanged   string
+	assigneeChanged string
+	changed         string
+}
+
+var (
+	storyDigestMessages = digestMessages{
+		created:         "Нове завдання: #%d %s [%s]",
+		statusChanged:   "Статус завдання змінено: #%d %s (%s -> %s)",
+		assigneeChanged: "Виконавця завдання змінено: #%d %s",
+		changed:         "Завдання змінено: #%d %s",
+	}
+	issueDigestMessages = digestMessages{
+		created:         "Новий запит: #%d %s [%s]",
+		statusChanged:   "Статус запиту змінено: #%d %s (%s -> %s)",
+		assigneeChanged: "Виконавця запиту змінено: #%d %s",
+		changed:         "Запит змінено: #%d %s",
+	}
+)
+
+// diffTrackedItems builds fresh digests for items and returns notifications for changes since last.
+// When last is empty the call only records a baseline.
+func diffTrackedItems(items map[int64]trackedItem, last map[int64]storage.TaskDigest, texts digestMessages) (map[int64]storage.TaskDigest, []itemNotification) {
+	baselineOnly := len(last) == 0
+
+	digests := make(map[int64]storage.TaskDigest, len(items))
+
+	var messages []itemNotification
+
+	for _, item := range items {
+		old, ok := last[item.ID]
+
+		digest, n := diffTrackedItem(item, old, ok, texts)
+		digests[item.ID] = digest
+
+		if !baselineOnly && n != nil {
+			messages = append(messages, *n)
+		}
+	}
+
+	return digests, messages
+}
+
+// diffTrackedItem builds the digest of one item and the notification for its change, if any.
+func diffTrackedItem(item trackedItem, old storage.TaskDigest, known bool, texts digestMessages) (storage.TaskDigest, *itemNotification) {
+	assignedTo := int64(0)
+	if item.AssignedTo != nil {
+		assignedTo = *item.AssignedTo
+	}
+
+	digest := storage.TaskDigest{
+		Status:        item.Status,
+		AssignedTo:    assignedTo,
+		ProjectID:     item.ProjectID,
+		Version:       item.Version,
+		LastHistoryID: old.LastHistoryID,
+	}
+
+	switch {
+	case !known:
+		return digest, &itemNotification{Text: fmt.Sprintf(texts.created, item.Ref, item.Subject, item.Status), Item: item}
+	case old.Status != digest.Status:
+		return digest, &itemNotification{Text: fmt.Sprintf(texts.statusChanged, item.Ref, item.Subject, old.Status, digest.Status), Item: item, Updated: true}
+	case old.AssignedTo != digest.AssignedTo:
+		return digest, &itemNotification{Text: fmt.Sprintf(texts.assigneeChanged, item.Ref, item.Subject), Item: item, Updated: true}
+	case old.Version != 0 && digest.Version != 0 && old.Version != digest.Version:
+		// Subject, description, tags, points, sprint and comments only show up as a new version.
+		return digest, &itemNotification{Text: fmt.Sprintf(texts.changed, item.Ref, item.Subject), Item: item, Updated: true}
+	default:
+		return digest, nil
+	}
+}
+
+// describeChangeFromHistory replaces the text of an update notification with the item's
+// history entries since the last reported one and records the newest entry in digest.
+// The notification keeps its plain text if the history cannot be fetched.
+func describeChangeFromHistory(ctx context.Context, client *taiga.Client, n *itemNotification, digest *storage.TaskDigest) {
+	entries, err := client.ListHistory(ctx, n.Item.Kind, n.Item.ID)
+	if err != nil {
+		log.Printf("list history: kind=%s id=%d err=%v", n.Item.Kind, n.Item.ID, err)
+		return
+	}
+
+	if len(entries) == 0 {
+		return
+	}
+
+	fresh := taiga.HistorySince(entries, digest.LastHistoryID)
+	digest.LastHistoryID = entries[len(entries)-1].ID
+
+	var lines []string
+	for _, entry := range fresh {
+		lines = append(lines, formatHistoryEntry(entry, n.Item.Ref)...)
+	}
+
+	if len(lines) > 0 {
+		n.Text = strings.Join(lines, "\n")
+	}
+}
+
+// maxHistoryCommentLength bounds how much of a comment a notification quotes.
+const maxHistoryCommentLength = 500
+
+// formatHistoryEntry describes one history entry as lines like "Olena переносить #42 з In progress → Review".
+func formatHistoryEntry(entry taiga.HistoryEntry, ref int64) []string {
+	if entry.IsHidden || entry.Type == taiga.HistoryTypeDelete {
+		return nil
+	}
+
+	author := entry.User.DisplayName()
+	if author == "" {
+		author = "Хтось"
+	}
+
+	var (
+		lines  []string
+		others []string
+	)
+
+	for _, field := range entry.Fields() {
+		switch field {
+		case "status":
+			if from, to, ok := entry.ValueChange(field); ok {
+				lines = append(lines, fmt.Sprintf("%s переносить #%d з %s → %s", author, ref, from, to))
+			}
+		case "assigned_to":
+			if _, to, ok := entry.ValueChange(field); ok {
+				if to == "" {
+					lines = append(lines, fmt.Sprintf("%s знімає виконавця з #%d", author, ref))
+				} else {
+					lines = append(lines, fmt.Sprintf("%s призначає #%d на %s", author, ref, to))
+				}
+			}
+		case "assigned_users":
+			if from, to, ok := entry.ValueChange(field); ok {
+				lines = append(lines, fmt.Sprintf("%s змінює виконавців #%d: %s → %s", author, ref, orDash(from), orDash(to)))
+			}
+		case "subject":
+			if from, to, ok := entry.ValueChange(field); ok {
+				lines = append(lines, fmt.Sprintf("%s перейменовує #%d: «%s» → «%s»", author, ref, from, to))
+			}
+		case "description", "description_diff":
+			if !slices.Contains(lines, fmt.Sprintf("%s змінює опис #%d", author, ref)) {
+				lines = append(lines, fmt.Sprintf("%s змінює опис #%d", author, ref))
+			}
+		case "tags":
+			if added, removed, ok := entry.ListChange(field); ok && len(added)+len(removed) > 0 {
+				var changes []string
+				for _, tag := range added {
+					changes = append(changes, "+"+tag)
+				}
+
+				for _, tag := range removed {
+					changes = append(changes, "−"+tag)
+				}
+
+				lines = append(lines, fmt.Sprintf("%s змінює теги #%d: %s", author, ref, strings.Join(changes, ", ")))
+			}
+		case "points":
+			if points, ok := entry.PointsChange(); ok && len(points) > 0 {
+				roles := make([]string, 0, len(points))
+				for role := range points {
+					roles = append(roles, role)
+				}
+
+				sort.Strings(roles)
+
+				changes := make([]string, 0, len(roles))
+				for _, role := range roles {
+					changes = append(changes, fmt.Sprintf("%s %s → %s", role, orDash(points[role][0]), orDash(points[role][1])))
+				}
+
+				lines = append(lines, fmt.Sprintf("%s змінює бали #%d: %s", author, ref, strings.Join(changes, ", ")))
+			}
+		case "milestone":
+			if from, to, ok := entry.ValueChange(field); ok {
+				if to == "" {
+					lines = append(lines, fmt.Sprintf("%s прибирає #%d зі спринту %s", author, ref, from))
+				} else {
+					lines = append(lines, fmt.Sprintf("%s переносить #%d у спринт %s", author, ref, to))
+				}
+			}
+		default:
+			others = append(others, field)
+		}
+	}
+
+	if len(others) > 0 {
+		lines = append(lines, fmt.Sprintf("%s змінює #%d: %s", author, ref, strings.Join(others, ", ")))
+	}
+
+	if entry.HasComment() {
+		comment := strings.TrimSpace(entry.Comment)
+		if runes := []rune(comment); len(runes) > maxHistoryCommentLength {
+			comment = string(runes[:maxHistoryCommentLength]) + "…"
+		}
+
+		lines = append(lines, fmt.Sprintf("%s коментує #%d:\n%s", author, ref, comment))
+	}
+
+	return lines
+}
+
+// orDash returns value, or a dash for an empty one.
+func orDash(value string) string {
+	if value == "" {
+		return "—"
+	}
+
+	return value
+}
+
+// keepSkippedDigests carries over digests of projects that were not polled this cycle.
+func keepSkippedDigests(digests, last map[int64]storage.TaskDigest, skipProject func(projectID int64) bool) {
+	for id, digest := range last {
+		if _, ok := digests[id]; ok || digest.ProjectID == 0 || !skipProject(digest.ProjectID) {
+			continue
+		}
+
+		digests[id] = digest
+	}
+}
+
+// mergeConcurrentDigests keeps the digests that changed in the store while a poll was
+// running, e.g. from a webhook event, over the ones the poll computed from before.
+// Digests removed meanwhile stay removed.
+func mergeConcurrentDigests(polled, before, current map[int64]storage.TaskDigest) map[int64]storage.TaskDigest {
+	for id, digest := range current {
+		if previous, ok := before[id]; !ok || previous != digest {
+			polled[id] = digest
+		}
+	}
+
+	for id := range before {
+		if _, ok := current[id]; !ok {
+			delete(polled, id)
+		}
+	}
+
+	return polled
+}
+
+// handleWebhookEvent turns a Taiga webhook event into the notifications polling would send for it.
+// The event was signed with the project's secret, not with the user's token, so a user is only
+// notified after checking with their own token that they can read the project.
+func handleWebhookEvent(ctx context.Context, bot *telego.Bot, store *storage.Store, tokens *taiga.TokenSources, taigaBaseURL string, projectID int64, event webhook.Event) {
+	var (
+		kind  taiga.ItemKind
+		texts digestMessages
+	)
+
+	switch event.Type {
+	case "userstory":
+		kind, texts = taiga.KindUserStory, storyDigestMessages
+	case "issue":
+		kind, texts = taiga.KindIssue, issueDigestMessages
+	default:
+		return
+	}
+
+	item := trackedItem{Kind: kind, ID: event.Data.ID, Ref: event.Data.Ref, ProjectID: projectID, Subject: event.Data.Subject, Version: event.Data.Version}
+	if event.Data.Status != nil {
+		item.Status = event.Data.Status.Name
+	}
+
+	if event.Data.AssignedTo != nil {
+		assignedTo := event.Data.AssignedTo.ID
+		item.AssignedTo = &assignedTo
+	}
+
+	for _, link := range store.List() {
+		if link.NotifyChatID == nil || link.PollingDisabled {
+			continue
+		}
+
+		last, setDigest := link.LastTaskStates, store.SetTaskDigest
+		if kind == taiga.KindIssue {
+			last, setDigest = link.LastIssueStates, store.SetIssueDigest
+		}
+
+		assignedToUser := item.AssignedTo != nil && *item.AssignedTo == link.TaigaUserID
+		if event.Action == webhook.ActionDelete || (!assignedToUser && !slices.Contains(link.WatchedProjects, projectID)) {
+			_ = setDigest(link.TelegramID, item.ID, nil)
+			continue
+		}
+
+		if !canReadProject(ctx, store, tokens, taigaBaseURL, link, projectID, time.Now()) {
+			continue
+		}
+
+		old, known := last[item.ID]
+
+		digest, n := diffTrackedItem(item, old, known, texts)
+		if n == nil && known && event.Action == webhook.ActionChange && event.Change != nil {
+			// A comment does not always bump the version, but the event still reports it.
+			n = &itemNotification{Text: fmt.Sprintf(texts.changed, item.Ref, item.Subject), Item: item, Updated: true}
+		}
+
+		if n != nil && n.Updated {
+			describeChangeFromEvent(n, event)
+
+			// The history also advances LastHistoryID, so polling does not announce the entries again.
+			if client, err := newUserClient(taigaBaseURL, store, tokens, link); err == nil {
+				describeChangeFromHistory(ctx, client, n, &digest)
+			}
+		}
+
+		if n != nil {
+			sendItemNotification(ctx, bot, store, *link.NotifyChatID, *n)
+		}
+
+		// A nil map means polling has not recorded its baseline yet; storing a single
+		// digest now would make the first poll report every existing item as new.
+		if last != nil {
+			_ = setDigest(link.TelegramID, item.ID, &digest)
+		}
+	}
+}
+
+// describeChangeFromEvent replaces the text of an update notification with the author,
+// changed fields and comment of a webhook event, as a fallback when the history is unavailable.
+func describeChangeFromEvent(n *itemNotification, event webhook.Event) {
+	if event.Change == nil {
+		return
+	}
+
+	author := event.By.FullName
+	if author == "" {
+		author = event.By.Username
+	}
+
+	if author == "" {
+		author = "Хтось"
+	}
+
+	var lines []string
+
+	if len(event.Change.Diff) > 0 {
+		fields := make([]string, 0, len(event.Change.Diff))
+		for field := range event.Change.Diff {
+			fields = append(fields, field)
+		}
+
+		sort.Strings(fields)
+
+		lines = append(lines, fmt.Sprintf("%s змінює #%d: %s", author, n.Item.Ref, strings.Join(fields, ", ")))
+	}
+
+	if comment := strings.TrimSpace(event.Change.Comment); comment != "" {
+		if runes := []rune(comment); len(runes) > maxHistoryCommentLength {
+			comment = string(runes[:maxHistoryCommentLength]) + "…"
+		}
+
+		lines = append(lines, fmt.Sprintf("%s коментує #%d:\n%s", author, n.Item.Ref, comment))
+	}
+
+	if len(lines) > 0 {
+		n.Text = strings.Join(lines, "\n")
+	}
+}
+
+// canReadProject reports whether the user's Taiga token can read a project, reusing answers
+// for projectAccessTTL. A check that fails for another reason than a refusal is not cached.
+func canReadProject(ctx context.Context, store *storage.Store, tokens *taiga.TokenSources, taigaBaseURL string, link storage.UserLink, projectID int64, now time.Time) bool {
+	key := projectAccessKey{TelegramID: link.TelegramID, TaigaUserID: link.TaigaUserID, ProjectID: projectID}
+
+	projectAccessMu.Lock()
+	entry, ok := projectAccess[key]
+	projectAccessMu.Unlock()
+
+	if ok && now.Before(entry.Expires) {
+		return entry.Allowed
+	}
+
+	client, err := newUserClient(taigaBaseURL, store, tokens, link)
+	if err != nil {
+		return false
+	}
+
+	_, err = client.GetProject(ctx, projectID)
+
+	refused := errors.Is(err, taiga.ErrNotFound) || errors.Is(err, taiga.ErrForbidden) || tokensRejected(err)
+	if err != nil && !refused {
+		log.Printf("check project access: telegram_id=%d project_id=%d err=%v", link.TelegramID, projectID, err)
+		return false
+	}
+
+	projectAccessMu.Lock()
+	defer projectAccessMu.Unlock()
+
+	for k, entry := range projectAccess {
+		if now.After(entry.Expires) {
+			delete(projectAccess, k)
+		}
+	}
+
+	projectAccess[key] = projectAccessEntry{Allowed: err == nil, Expires: now.Add(projectAccessTTL)}
+
+	return err == nil
+}
+
+// sendItemNotification delivers a change message and remembers its item so replies become Taiga comments.
+func sendItemNotification(ctx context.Context, bot *telego.Bot, store *storage.Store, chatID int64, n itemNotification) {
+	sent, err := bot.SendMessage(ctx, tu.Message(tu.ID(chatID), n.Text))
+	if err != nil || sent == nil {
+		return
+	}
+
+	target := storage.NotificationTarget{
+		Kind:      string(n.Item.Kind),
+		ItemID:    n.Item.ID,
+		Ref:       n.Item.Ref,
+		ProjectID: n.Item.ProjectID,
+	}
+	if err := store.SaveNotificationTarget(chatID, sent.MessageID, target); err != nil {
+		log.Printf("save notification target: chat_id=%d message_id=%d err=%v", chatID, sent.MessageID, err)
+	}
+}
+
+// pollNotifications periodically diffs tracked items of every linked user.
+// Projects for which skipProject reports true are served by webhooks and left out.
+func pollNotifications(ctx context.Context, bot *telego.Bot, store *storage.Store, tokens *taiga.TokenSources, taigaBaseURL string, interval time.Duration, skipProject func(projectID int64) bool) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
@@ -1339,86 +3813,138 @@ func pollNotifications(ctx context.Context, bot *telego.Bot, store *storage.Stor
 		case <-ticker.C:
 			links := store.List()
 			for _, link := range links {
-				last := link.LastTaskStates
-				if last == nil {
-					last = make(map[int64]storage.TaskDigest)
-				}
-
-				if link.NotifyChatID == nil {
+				if link.NotifyChatID == nil || link.PollingDisabled {
 					continue
 				}
 				destinationChatID := *link.NotifyChatID
 
-				baselineOnly := len(last) == 0
+				warnRefreshExpiry(ctx, bot, store, link, destinationChatID, time.Now())
 
-				linkCopy := link
-				client, err := taiga.NewClientWithTokens(taigaBaseURL, linkCopy.TaigaToken, linkCopy.TaigaRefresh, func(authToken, refreshToken string) {
-					updated := linkCopy
-					updated.TaigaToken = authToken
-					updated.TaigaRefresh = refreshToken
-					_ = store.Save(updated)
-				})
+				client, err := newUserClient(taigaBaseURL, store, tokens, link)
 				if err != nil {
 					continue
 				}
 
-				allStories := make(map[int64]taiga.UserStory)
+				allStories := make(map[int64]trackedItem)
+				allIssues := make(map[int64]trackedItem)
 				assigned := link.TaigaUserID
 
+				// A kind is only diffed when every list of it was fetched: a partial list would
+				// drop the digests of the missing items and announce them as new next time.
+				storiesFetched, issuesFetched := true, true
+
 				storiesAssigned, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{AssignedTo: &assigned})
+				if tokensRejected(err) {
+					disablePolling(ctx, bot, store, link.TelegramID, destinationChatID)
+					continue
+				}
+
+				storiesFetched = storiesFetched && err == nil
 				if err == nil {
 					for _, us := range storiesAssigned {
-						allStories[us.ID] = us
+						if skipProject(us.Project) {
+							continue
+						}
+
+						allStories[us.ID] = trackedItem{Kind: taiga.KindUserStory, ID: us.ID, Ref: us.Ref, ProjectID: us.Project, Subject: us.Subject, Status: us.StatusExtraInfo.Name, AssignedTo: us.AssignedTo, Version: us.Version}
+					}
+				}
+
+				issuesAssigned, err := client.ListIssues(context.Background(), taiga.ListIssuesParams{AssignedTo: &assigned})
+				issuesFetched = issuesFetched && err == nil
+				if err == nil {
+					for _, issue := range issuesAssigned {
+						if skipProject(issue.Project) {
+							continue
+						}
+
+						allIssues[issue.ID] = trackedItem{Kind: taiga.KindIssue, ID: issue.ID, Ref: issue.Ref, ProjectID: issue.Project, Subject: issue.Subject, Status: issue.StatusExtraInfo.Name, AssignedTo: issue.AssignedTo, Version: issue.Version}
 					}
 				}
 
 				for _, projectID := range link.WatchedProjects {
-					storiesProject, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{ProjectID: projectID})
-					if err != nil {
+					if skipProject(projectID) {
 						continue
 					}
 
-					for _, us := range storiesProject {
-						allStories[us.ID] = us
+					storiesProject, err := client.ListUserStories(context.Background(), taiga.ListUserStoriesParams{ProjectID: projectID})
+					storiesFetched = storiesFetched && err == nil
+					if err == nil {
+						for _, us := range storiesProject {
+							allStories[us.ID] = trackedItem{Kind: taiga.KindUserStory, ID: us.ID, Ref: us.Ref, ProjectID: us.Project, Subject: us.Subject, Status: us.StatusExtraInfo.Name, AssignedTo: us.AssignedTo, Version: us.Version}
+						}
+					}
+
+					issuesProject, err := client.ListIssues(context.Background(), taiga.ListIssuesParams{ProjectID: projectID})
+					issuesFetched = issuesFetched && err == nil
+					if err == nil {
+						for _, issue := range issuesProject {
+							allIssues[issue.ID] = trackedItem{Kind: taiga.KindIssue, ID: issue.ID, Ref: issue.Ref, ProjectID: issue.Project, Subject: issue.Subject, Status: issue.StatusExtraInfo.Name, AssignedTo: issue.AssignedTo, Version: issue.Version}
+						}
 					}
 				}
 
-				newDigests := make(map[int64]storage.TaskDigest, len(allStories))
-				for _, us := range allStories {
-					assignedTo := int64(0)
-					if us.AssignedTo != nil {
-						assignedTo = *us.AssignedTo
-					}
+				var (
+					storyDigests, issueDigests   map[int64]storage.TaskDigest
+					storyMessages, issueMessages []itemNotification
+				)
+
+				if storiesFetched {
+					storyDigests, storyMessages = diffTrackedItems(allStories, link.LastTaskStates, storyDigestMessages)
+				}
+
+				if issuesFetched {
+					issueDigests, issueMessages = diffTrackedItems(allIssues, link.LastIssueStates, issueDigestMessages)
+				}
+
+				if storiesFetched {
+					keepSkippedDigests(storyDigests, link.LastTaskStates, skipProject)
+				}
+
+				if issuesFetched {
+					keepSkippedDigests(issueDigests, link.LastIssueStates, skipProject)
+				}
 
-					digest := storage.TaskDigest{
-						Status:     us.StatusExtraInfo.Name,
-						AssignedTo: assignedTo,
+				for _, n := range storyMessages {
+					if n.Updated {
+						digest := storyDigests[n.Item.ID]
+						describeChangeFromHistory(ctx, client, &n, &digest)
+						storyDigests[n.Item.ID] = digest
 					}
 
-					newDigests[us.ID] = digest
+					sendItemNotification(ctx, bot, store, destinationChatID, n)
+				}
 
-					if baselineOnly {
-						continue
+				for _, n := range issueMessages {
+					if n.Updated {
+						digest := issueDigests[n.Item.ID]
+						describeChangeFromHistory(ctx, client, &n, &digest)
+						issueDigests[n.Item.ID] = digest
 					}
 
-					old, ok := last[us.ID]
+					sendItemNotification(ctx, bot, store, destinationChatID, n)
+				}
+
+				_ = store.Transaction(func(tx *storage.Tx) error {
+					current, ok := tx.Get(link.TelegramID)
 					if !ok {
-						_, _ = bot.SendMessage(ctx, tu.Message(tu.ID(destinationChatID), fmt.Sprintf("Нове завдання: #%d %s [%s]", us.Ref, us.Subject, us.StatusExtraInfo.Name)))
-						continue
+						return nil
 					}
 
-					if old.Status != digest.Status {
-						_, _ = bot.SendMessage(ctx, tu.Message(tu.ID(destinationChatID), fmt.Sprintf("Статус завдання змінено: #%d %s (%s -> %s)", us.Ref, us.Subject, old.Status, digest.Status)))
-						continue
+					// A kind that failed to fetch keeps its digests until the next cycle.
+					if storiesFetched {
+						err := tx.SetTaskStates(link.TelegramID, mergeConcurrentDigests(storyDigests, link.LastTaskStates, current.LastTaskStates))
+						if err != nil {
+							return err
+						}
 					}
 
-					if old.AssignedTo != digest.AssignedTo {
-						_, _ = bot.SendMessage(ctx, tu.Message(tu.ID(destinationChatID), fmt.Sprintf("Виконавця завдання змінено: #%d %s", us.Ref, us.Subject)))
-						continue
+					if issuesFetched {
+						return tx.SetIssueStates(link.TelegramID, mergeConcurrentDigests(issueDigests, link.LastIssueStates, current.LastIssueStates))
 					}
-				}
 
-				_ = store.UpdateTaskState(link.TelegramID, newDigests)
+					return nil
+				})
 			}
 		}
 	}
@@ -16,25 +16,64 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/iho/taigagra/internal/storage"
 )
 
 // Config holds application level configuration values.
 type Config struct {
 	TelegramToken string
 	TaigaBaseURL  string
+	TaigaWebURL   string
 	StoragePath   string
-	PollInterval  time.Duration
+	// StorageBackend is StorageBackendJSON or StorageBackendJournal.
+	StorageBackend string
+	// TokenKey, when set, encrypts Taiga tokens in the store; PreviousTokenKeys
+	// still decrypt tokens sealed before a key rotation.
+	TokenKey          []byte
+	PreviousTokenKeys [][]byte
+	PollInterval      time.Duration
+	MetadataTTL       time.Duration
+	WebhookAddr       string
+	WebhookSecrets    map[int64]string
+	WebhookWindow     time.Duration
+	// TaigaAppID and TaigaAppKey identify the bot as a Taiga external application;
+	// when set, /login stores an application token instead of auth and refresh tokens.
+	TaigaAppID  string
+	TaigaAppKey string
 }
 
 const (
 	taigaBaseURLKey  = "TAIGA_BASE_URL"
+	taigaWebURLKey   = "TAIGA_WEB_URL"
 	telegramTokenKey = "TELEGRAM_BOT_TOKEN"
 	storagePathKey   = "LINK_STORAGE_PATH"
+	storageKindKey   = "STORAGE_BACKEND"
+	tokenKeyKey      = "TOKEN_ENCRYPTION_KEY"
+	tokenKeyFileKey  = "TOKEN_ENCRYPTION_KEY_FILE"
+	previousKeysKey  = "TOKEN_ENCRYPTION_PREVIOUS_KEYS"
 	pollIntervalKey  = "POLL_INTERVAL_SECONDS"
+	metadataTTLKey   = "METADATA_CACHE_TTL_SECONDS"
+	webhookAddrKey   = "WEBHOOK_LISTEN_ADDR"
+	webhookSecretKey = "WEBHOOK_SECRETS"
+	webhookFallKey   = "WEBHOOK_FALLBACK_SECONDS"
+	taigaAppIDKey    = "TAIGA_APP_ID"
+	taigaAppKeyKey   = "TAIGA_APP_KEY"
+)
+
+// Storage backends selectable with STORAGE_BACKEND.
+const (
+	// StorageBackendJSON rewrites one JSON file on every change.
+	StorageBackendJSON = "json"
+	// StorageBackendJournal appends changes to a journal compacted into the JSON file.
+	StorageBackendJournal = "journal"
 )
 
 // Load reads configuration from the environment applying reasonable defaults where possible.
@@ -49,9 +88,16 @@ func Load() (Config, error) {
 		taigaBaseURL = "https://api.taiga.io/api/v1"
 	}
 
-	storagePath := os.Getenv(storagePathKey)
-	if storagePath == "" {
-		storagePath = "taiga_links.json"
+	taigaWebURL := os.Getenv(taigaWebURLKey)
+	if taigaWebURL == "" {
+		taigaWebURL = webURLFromAPI(taigaBaseURL)
+	}
+
+	taigaWebURL = strings.TrimRight(taigaWebURL, "/")
+
+	cfg, err := LoadStorage()
+	if err != nil {
+		return Config{}, err
 	}
 
 	pollInterval := 30 * time.Second
@@ -68,10 +114,204 @@ func Load() (Config, error) {
 		pollInterval = time.Duration(seconds) * time.Second
 	}
 
+	metadataTTL := 10 * time.Minute
+	if raw := os.Getenv(metadataTTLKey); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s: %w", metadataTTLKey, err)
+		}
+
+		if seconds <= 0 {
+			return Config{}, fmt.Errorf("%s must be positive", metadataTTLKey)
+		}
+
+		metadataTTL = time.Duration(seconds) * time.Second
+	}
+
+	webhookSecrets, err := parseWebhookSecrets(os.Getenv(webhookSecretKey))
+	if err != nil {
+		return Config{}, err
+	}
+
+	webhookListenAddr := os.Getenv(webhookAddrKey)
+	if webhookListenAddr != "" && len(webhookSecrets) == 0 {
+		return Config{}, fmt.Errorf("%s requires %s", webhookAddrKey, webhookSecretKey)
+	}
+
+	webhookFallback := time.Hour
+	if raw := os.Getenv(webhookFallKey); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s: %w", webhookFallKey, err)
+		}
+
+		if seconds <= 0 {
+			return Config{}, fmt.Errorf("%s must be positive", webhookFallKey)
+		}
+
+		webhookFallback = time.Duration(seconds) * time.Second
+	}
+
+	taigaAppID := strings.TrimSpace(os.Getenv(taigaAppIDKey))
+	taigaAppKey := os.Getenv(taigaAppKeyKey)
+	if (taigaAppID == "") != (taigaAppKey == "") {
+		return Config{}, fmt.Errorf("%s and %s must be set together", taigaAppIDKey, taigaAppKeyKey)
+	}
+
+	cfg.TelegramToken = telegramToken
+	cfg.TaigaBaseURL = taigaBaseURL
+	cfg.TaigaWebURL = taigaWebURL
+	cfg.PollInterval = pollInterval
+	cfg.MetadataTTL = metadataTTL
+	cfg.WebhookAddr = webhookListenAddr
+	cfg.WebhookSecrets = webhookSecrets
+	cfg.WebhookWindow = webhookFallback
+	cfg.TaigaAppID = taigaAppID
+	cfg.TaigaAppKey = taigaAppKey
+
+	return cfg, nil
+}
+
+// LoadStorage reads only the storage settings, for commands that work on the store without the bot.
+func LoadStorage() (Config, error) {
+	storagePath := os.Getenv(storagePathKey)
+	if storagePath == "" {
+		storagePath = "taiga_links.json"
+	}
+
+	storageBackend := strings.ToLower(strings.TrimSpace(os.Getenv(storageKindKey)))
+	switch storageBackend {
+	case "":
+		storageBackend = StorageBackendJSON
+	case StorageBackendJSON, StorageBackendJournal:
+	default:
+		return Config{}, fmt.Errorf("invalid %s %q: expected %s or %s", storageKindKey, storageBackend, StorageBackendJSON, StorageBackendJournal)
+	}
+
+	tokenKey, err := loadTokenKey()
+	if err != nil {
+		return Config{}, err
+	}
+
+	var previousKeys [][]byte
+	for raw := range strings.SplitSeq(os.Getenv(previousKeysKey), ",") {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+
+		key, err := decodeTokenKey(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s: %w", previousKeysKey, err)
+		}
+
+		previousKeys = append(previousKeys, key)
+	}
+
+	if tokenKey == nil && len(previousKeys) > 0 {
+		return Config{}, fmt.Errorf("%s requires %s or %s", previousKeysKey, tokenKeyKey, tokenKeyFileKey)
+	}
+
 	return Config{
-		TelegramToken: telegramToken,
-		TaigaBaseURL:  taigaBaseURL,
-		StoragePath:   storagePath,
-		PollInterval:  pollInterval,
+		StoragePath:       storagePath,
+		StorageBackend:    storageBackend,
+		TokenKey:          tokenKey,
+		PreviousTokenKeys: previousKeys,
 	}, nil
 }
+
+// loadTokenKey reads the token encryption key from the environment or from a key file.
+// It returns nil when token encryption is not configured.
+func loadTokenKey() ([]byte, error) {
+	raw := os.Getenv(tokenKeyKey)
+	path := os.Getenv(tokenKeyFileKey)
+
+	switch {
+	case raw != "" && path != "":
+		return nil, fmt.Errorf("set only one of %s and %s", tokenKeyKey, tokenKeyFileKey)
+	case path != "":
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", tokenKeyFileKey, err)
+		}
+
+		key, err := decodeTokenKey(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", tokenKeyFileKey, err)
+		}
+
+		return key, nil
+	case raw != "":
+		key, err := decodeTokenKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", tokenKeyKey, err)
+		}
+
+		return key, nil
+	default:
+		return nil, nil
+	}
+}
+
+// decodeTokenKey decodes a base64 AES-256 key, e.g. one made with `openssl rand -base64 32`.
+func decodeTokenKey(raw string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, fmt.Errorf("expected a base64 key: %w", err)
+	}
+
+	if len(key) != storage.TokenKeySize {
+		return nil, fmt.Errorf("expected a %d byte key, got %d bytes", storage.TokenKeySize, len(key))
+	}
+
+	return key, nil
+}
+
+// webURLFromAPI guesses the Taiga web UI address from the API address:
+// the hosted api.taiga.io serves its UI from tree.taiga.io, while
+// self-hosted instances usually serve both from one host with the API under /api/v1.
+func webURLFromAPI(apiURL string) string {
+	parsed, err := url.Parse(apiURL)
+	if err != nil || parsed.Host == "" {
+		return ""
+	}
+
+	if parsed.Host == "api.taiga.io" {
+		parsed.Host = "tree.taiga.io"
+	}
+
+	path := strings.TrimRight(parsed.Path, "/")
+	path = strings.TrimSuffix(path, "/api/v1")
+
+	parsed.Path = path
+	parsed.RawQuery = ""
+	parsed.Fragment = ""
+
+	return parsed.String()
+}
+
+// parseWebhookSecrets parses "<project_id>=<secret>" pairs separated by commas.
+func parseWebhookSecrets(raw string) (map[int64]string, error) {
+	secrets := make(map[int64]string)
+
+	// Errors name entries by position only: an entry may carry a secret.
+	for i, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		idRaw, secret, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(secret) == "" {
+			return nil, fmt.Errorf("invalid %s entry #%d: expected <project_id>=<secret>", webhookSecretKey, i+1)
+		}
+
+		projectID, err := strconv.ParseInt(strings.TrimSpace(idRaw), 10, 64)
+		if err != nil || projectID <= 0 {
+			return nil, fmt.Errorf("invalid %s entry #%d: bad project id", webhookSecretKey, i+1)
+		}
+
+		secrets[projectID] = strings.TrimSpace(secret)
+	}
+
+	return secrets, nil
+}
@@ -0,0 +1,224 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"maps"
+	"sort"
+)
+
+// Backend persists the state of a Store.
+// The Store keeps the whole state in memory and calls Commit once per change,
+// always under its lock, so backends need no locking of their own.
+type Backend interface {
+	// Load returns the persisted state, or an empty Snapshot if nothing was stored yet.
+	Load() (Snapshot, error)
+	// Commit durably records change, after which the in-memory state is state.
+	// Backends that rewrite everything may ignore change; journals may ignore state.
+	Commit(change Change, state *Snapshot) error
+	// Rewrite replaces everything stored with state.
+	Rewrite(state *Snapshot) error
+	// Close flushes pending data and releases the backend.
+	Close() error
+}
+
+// partialCommitter is implemented by backends whose Commit reads state only some of the time.
+// Wrappers that have to prepare the state, like EncryptedBackend, ask first and pass nil otherwise.
+type partialCommitter interface {
+	// commitNeedsState reports whether the next Commit reads its state argument.
+	commitNeedsState() bool
+}
+
+// Snapshot is the whole state of a Store. Its JSON form is the store file format.
+type Snapshot struct {
+	Links               map[int64]UserLink                   `json:"links"`
+	ProjectUserMappings map[int64]map[int64]int64            `json:"project_user_mappings,omitempty"`
+	TelegramUsernames   map[string]int64                     `json:"telegram_usernames,omitempty"`
+	NotificationTargets map[int64]map[int]NotificationTarget `json:"notification_targets,omitempty"`
+	ChatProjects        map[int64]ChatProject                `json:"chat_projects,omitempty"`
+	SchemaVersion       int                                  `json:"schema_version"`
+}
+
+// init allocates the maps a loaded snapshot left nil.
+func (s *Snapshot) init() {
+	if s.Links == nil {
+		s.Links = make(map[int64]UserLink)
+	}
+
+	if s.ProjectUserMappings == nil {
+		s.ProjectUserMappings = make(map[int64]map[int64]int64)
+	}
+
+	if s.TelegramUsernames == nil {
+		s.TelegramUsernames = make(map[string]int64)
+	}
+
+	if s.NotificationTargets == nil {
+		s.NotificationTargets = make(map[int64]map[int]NotificationTarget)
+	}
+
+	if s.ChatProjects == nil {
+		s.ChatProjects = make(map[int64]ChatProject)
+	}
+}
+
+// ChangeOp names the kind of a Change.
+type ChangeOp string
+
+// Kinds of store changes.
+const (
+	OpPutLink                  ChangeOp = "put_link"
+	OpDeleteLink               ChangeOp = "delete_link"
+	OpSetTaskStates            ChangeOp = "set_task_states"
+	OpSetIssueStates           ChangeOp = "set_issue_states"
+	OpSetTaskDigest            ChangeOp = "set_task_digest"
+	OpSetIssueDigest           ChangeOp = "set_issue_digest"
+	OpSetProjectUserMapping    ChangeOp = "set_project_user_mapping"
+	OpRemoveProjectUserMapping ChangeOp = "remove_project_user_mapping"
+	OpSetTelegramUsername      ChangeOp = "set_telegram_username"
+	OpSetNotificationTarget    ChangeOp = "set_notification_target"
+	OpSetChatProject           ChangeOp = "set_chat_project"
+	// OpBatch applies Changes in order; transactions persist as one batch.
+	OpBatch ChangeOp = "batch"
+)
+
+// Change is one modification of the store state. Only the fields its Op uses are set.
+// Applying a change twice has the same effect as applying it once, so a journal
+// replayed over a snapshot that already contains some of its changes stays correct.
+type Change struct {
+	Link               *UserLink            `json:"link,omitempty"`
+	Digests            map[int64]TaskDigest `json:"digests,omitempty"`
+	Digest             *TaskDigest          `json:"digest,omitempty"`
+	NotificationTarget *NotificationTarget  `json:"notification_target,omitempty"`
+	ChatProject        *ChatProject         `json:"chat_project,omitempty"`
+	Changes            []Change             `json:"changes,omitempty"`
+	Op                 ChangeOp             `json:"op"`
+	Username           string               `json:"username,omitempty"`
+	TelegramID         int64                `json:"telegram_id,omitempty"`
+	ProjectID          int64                `json:"project_id,omitempty"`
+	TaigaUserID        int64                `json:"taiga_user_id,omitempty"`
+	ChatID             int64                `json:"chat_id,omitempty"`
+	ItemID             int64                `json:"item_id,omitempty"`
+	MessageID          int                  `json:"message_id,omitempty"`
+}
+
+// apply performs the change on state. Changes are validated by the Store before they are
+// made, so ones that no longer fit the state (e.g. for a deleted link) are ignored.
+func (c Change) apply(state *Snapshot) {
+	state.init()
+
+	switch c.Op {
+	case OpPutLink:
+		if c.Link != nil {
+			state.Links[c.Link.TelegramID] = *c.Link
+		}
+	case OpDeleteLink:
+		delete(state.Links, c.TelegramID)
+	case OpSetTaskStates, OpSetIssueStates:
+		link, ok := state.Links[c.TelegramID]
+		if !ok {
+			return
+		}
+
+		if c.Op == OpSetTaskStates {
+			link.LastTaskStates = c.Digests
+		} else {
+			link.LastIssueStates = c.Digests
+		}
+
+		state.Links[c.TelegramID] = link
+	case OpSetTaskDigest, OpSetIssueDigest:
+		link, ok := state.Links[c.TelegramID]
+		if !ok {
+			return
+		}
+
+		m := &link.LastTaskStates
+		if c.Op == OpSetIssueDigest {
+			m = &link.LastIssueStates
+		}
+
+		// Links handed out by Get share their maps, so the map is replaced rather than mutated.
+		updated := maps.Clone(*m)
+		if updated == nil {
+			updated = make(map[int64]TaskDigest)
+		}
+
+		if c.Digest == nil {
+			delete(updated, c.ItemID)
+		} else {
+			updated[c.ItemID] = *c.Digest
+		}
+
+		*m = updated
+		state.Links[c.TelegramID] = link
+	case OpSetProjectUserMapping:
+		if state.ProjectUserMappings[c.ProjectID] == nil {
+			state.ProjectUserMappings[c.ProjectID] = make(map[int64]int64)
+		}
+
+		state.ProjectUserMappings[c.ProjectID][c.TelegramID] = c.TaigaUserID
+	case OpRemoveProjectUserMapping:
+		delete(state.ProjectUserMappings[c.ProjectID], c.TelegramID)
+
+		if len(state.ProjectUserMappings[c.ProjectID]) == 0 {
+			delete(state.ProjectUserMappings, c.ProjectID)
+		}
+	case OpSetTelegramUsername:
+		state.TelegramUsernames[c.Username] = c.TelegramID
+	case OpSetNotificationTarget:
+		if c.NotificationTarget != nil {
+			state.setNotificationTarget(c.ChatID, c.MessageID, *c.NotificationTarget)
+		}
+	case OpSetChatProject:
+		if c.ChatProject == nil {
+			delete(state.ChatProjects, c.ChatID)
+		} else {
+			state.ChatProjects[c.ChatID] = *c.ChatProject
+		}
+	case OpBatch:
+		for _, change := range c.Changes {
+			change.apply(state)
+		}
+	}
+}
+
+// setNotificationTarget records a target and keeps only the newest
+// maxNotificationTargetsPerChat messages of the chat.
+func (s *Snapshot) setNotificationTarget(chatID int64, messageID int, target NotificationTarget) {
+	targets := s.NotificationTargets[chatID]
+	if targets == nil {
+		targets = make(map[int]NotificationTarget)
+		s.NotificationTargets[chatID] = targets
+	}
+
+	targets[messageID] = target
+
+	if len(targets) <= maxNotificationTargetsPerChat {
+		return
+	}
+
+	ids := make([]int, 0, len(targets))
+	for id := range targets {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids[:len(ids)-maxNotificationTargetsPerChat] {
+		delete(targets, id)
+	}
+}
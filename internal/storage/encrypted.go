@@ -0,0 +1,429 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TokenKeySize is the length of a token encryption key: AES-256.
+const TokenKeySize = 32
+
+// sealedTokenPrefix starts every encrypted token value. Taiga tokens never start with it.
+const sealedTokenPrefix = "enc:v1:"
+
+// IsSealedToken reports whether a stored token value is encrypted.
+func IsSealedToken(value string) bool {
+	return strings.HasPrefix(value, sealedTokenPrefix)
+}
+
+// TokenCipher encrypts token values with AES-GCM envelope encryption: every value gets
+// its own random data key, which is stored next to it wrapped with the master key.
+// Sealed values read "enc:v1:<key id>:<wrapped data key>:<ciphertext>".
+type TokenCipher struct {
+	keys      map[string][]byte
+	currentID string
+}
+
+// NewTokenCipher returns a cipher sealing with key and also opening values sealed with
+// any of the previous keys, so the store can be re-encrypted after a key rotation.
+func NewTokenCipher(key []byte, previous ...[]byte) (*TokenCipher, error) {
+	c := &TokenCipher{keys: make(map[string][]byte)}
+
+	for i, k := range append([][]byte{key}, previous...) {
+		if len(k) != TokenKeySize {
+			return nil, fmt.Errorf("ключ шифрування токенів має бути %d байти, а не %d", TokenKeySize, len(k))
+		}
+
+		id := tokenKeyID(k)
+		if i == 0 {
+			c.currentID = id
+		}
+
+		c.keys[id] = k
+	}
+
+	return c, nil
+}
+
+// tokenKeyID names a master key without revealing it.
+func tokenKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:4])
+}
+
+// Seal encrypts a token value with the current key. Empty values stay empty.
+func (c *TokenCipher) Seal(plain string) (string, error) {
+	if plain == "" {
+		return "", nil
+	}
+
+	dataKey := make([]byte, TokenKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", err
+	}
+
+	wrapped, err := sealGCM(c.keys[c.currentID], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := sealGCM(dataKey, []byte(plain))
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding.EncodeToString
+
+	return sealedTokenPrefix + c.currentID + ":" + enc(wrapped) + ":" + enc(sealed), nil
+}
+
+// Open decrypts a token value. Plaintext values are returned as they are; current
+// reports whether the value is already sealed with the current key.
+func (c *TokenCipher) Open(value string) (plain string, current bool, err error) {
+	if !IsSealedToken(value) {
+		return value, value == "", nil
+	}
+
+	parts := strings.Split(strings.TrimPrefix(value, sealedTokenPrefix), ":")
+	if len(parts) != 3 {
+		return "", false, errors.New("некоректний зашифрований токен")
+	}
+
+	key, ok := c.keys[parts[0]]
+	if !ok {
+		return "", false, fmt.Errorf("токен зашифровано невідомим ключем %s", parts[0])
+	}
+
+	wrapped, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", false, fmt.Errorf("некоректний зашифрований токен: %w", err)
+	}
+
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", false, fmt.Errorf("некоректний зашифрований токен: %w", err)
+	}
+
+	dataKey, err := openGCM(key, wrapped)
+	if err != nil {
+		return "", false, err
+	}
+
+	raw, err := openGCM(dataKey, sealed)
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(raw), parts[0] == c.currentID, nil
+}
+
+// sealGCM encrypts plain with AES-GCM under key and prepends the random nonce.
+func sealGCM(key, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plain, nil), nil
+}
+
+// openGCM reverses sealGCM.
+func openGCM(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("некоректний зашифрований токен")
+	}
+
+	plain, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("не вдалося розшифрувати токен: перевір ключ шифрування")
+	}
+
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// sealedTokens remembers the sealed form of a user's tokens, so unchanged tokens
+// are not encrypted again on every write.
+type sealedTokens struct {
+	token, refresh             string
+	sealedToken, sealedRefresh string
+}
+
+// EncryptedBackend stores the Taiga tokens of links encrypted by a TokenCipher and
+// hands them to the Store decrypted. Plaintext tokens and tokens sealed with a previous
+// key are re-encrypted with the current key as soon as the store is loaded, and so are
+// the schema migration backups kept next to a store file.
+type EncryptedBackend struct {
+	inner  Backend
+	cipher *TokenCipher
+	sealed map[int64]sealedTokens
+}
+
+// NewEncryptedBackend wraps inner so that it only ever sees encrypted tokens.
+func NewEncryptedBackend(inner Backend, c *TokenCipher) *EncryptedBackend {
+	return &EncryptedBackend{inner: inner, cipher: c, sealed: make(map[int64]sealedTokens)}
+}
+
+// Load reads the state from the wrapped backend and decrypts the tokens.
+func (b *EncryptedBackend) Load() (Snapshot, error) {
+	state, err := b.inner.Load()
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	state.init()
+
+	upgrade := false
+
+	for id, link := range state.Links {
+		token, tokenCurrent, err := b.cipher.Open(link.TaigaToken)
+		if err != nil {
+			b.inner.Close()
+
+			return Snapshot{}, fmt.Errorf("не вдалося розшифрувати токени користувача %d: %w", id, err)
+		}
+
+		refresh, refreshCurrent, err := b.cipher.Open(link.TaigaRefresh)
+		if err != nil {
+			b.inner.Close()
+
+			return Snapshot{}, fmt.Errorf("не вдалося розшифрувати токени користувача %d: %w", id, err)
+		}
+
+		if tokenCurrent && refreshCurrent {
+			b.sealed[id] = sealedTokens{token: token, refresh: refresh, sealedToken: link.TaigaToken, sealedRefresh: link.TaigaRefresh}
+		} else {
+			upgrade = true
+		}
+
+		link.TaigaToken = token
+		link.TaigaRefresh = refresh
+		state.Links[id] = link
+	}
+
+	if upgrade {
+		if err := b.Rewrite(&state); err != nil {
+			b.inner.Close()
+
+			return Snapshot{}, fmt.Errorf("не вдалося зашифрувати токени: %w", err)
+		}
+	}
+
+	if file, ok := b.inner.(storeFile); ok {
+		if err := b.sealBackups(file.storePath()); err != nil {
+			b.inner.Close()
+
+			return Snapshot{}, fmt.Errorf("не вдалося зашифрувати резервні копії сховища: %w", err)
+		}
+	}
+
+	return state, nil
+}
+
+// storeFile is implemented by backends keeping the store in a file.
+type storeFile interface {
+	storePath() string
+}
+
+// sealBackups encrypts the schema migration backups of the store at path. A backup is
+// sealed as a whole, like a single token, because its tokens sit in the layout of an
+// older schema; TokenCipher.Open with the same key restores the original document.
+// Backups sealed with a previous key are sealed again with the current one.
+func (b *EncryptedBackend) sealBackups(path string) error {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+
+	prefix := filepath.Base(path) + ".v"
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".bak") {
+			continue
+		}
+
+		backup := filepath.Join(filepath.Dir(path), name)
+
+		raw, err := os.ReadFile(backup)
+		if err != nil {
+			return err
+		}
+
+		plain, current, err := b.cipher.Open(strings.TrimSpace(string(raw)))
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		if current {
+			continue
+		}
+
+		// Open returns plaintext as it is, so an unsealed backup keeps its exact bytes.
+		if !IsSealedToken(strings.TrimSpace(string(raw))) {
+			plain = string(raw)
+		}
+
+		sealed, err := b.cipher.Seal(plain)
+		if err != nil {
+			return err
+		}
+
+		if err := writeFileSynced(backup, []byte(sealed+"\n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Commit encrypts the tokens in change and passes it on. The whole state, which costs a
+// pass over every link to encrypt, is only sealed when the wrapped backend is going to read it.
+func (b *EncryptedBackend) Commit(change Change, state *Snapshot) error {
+	change, err := b.sealChange(change)
+	if err != nil {
+		return err
+	}
+
+	var sealedState *Snapshot
+
+	if partial, ok := b.inner.(partialCommitter); !ok || partial.commitNeedsState() {
+		sealedState, err = b.sealState(state)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.inner.Commit(change, sealedState)
+}
+
+// sealChange returns change with the tokens of the links it writes encrypted.
+func (b *EncryptedBackend) sealChange(change Change) (Change, error) {
+	if change.Link != nil {
+		link, err := b.sealLink(*change.Link)
+		if err != nil {
+			return change, err
+		}
+
+		change.Link = &link
+	}
+
+	if change.Op == OpDeleteLink {
+		delete(b.sealed, change.TelegramID)
+	}
+
+	if len(change.Changes) > 0 {
+		changes := make([]Change, len(change.Changes))
+		for i, inner := range change.Changes {
+			sealed, err := b.sealChange(inner)
+			if err != nil {
+				return change, err
+			}
+
+			changes[i] = sealed
+		}
+
+		change.Changes = changes
+	}
+
+	return change, nil
+}
+
+// Rewrite encrypts the tokens in state with the current key and rewrites the wrapped backend.
+func (b *EncryptedBackend) Rewrite(state *Snapshot) error {
+	sealedState, err := b.sealState(state)
+	if err != nil {
+		return err
+	}
+
+	return b.inner.Rewrite(sealedState)
+}
+
+// Close closes the wrapped backend.
+func (b *EncryptedBackend) Close() error {
+	return b.inner.Close()
+}
+
+// sealState returns a copy of state whose links carry encrypted tokens.
+func (b *EncryptedBackend) sealState(state *Snapshot) (*Snapshot, error) {
+	sealedState := *state
+	sealedState.Links = make(map[int64]UserLink, len(state.Links))
+
+	for id, link := range state.Links {
+		sealedLink, err := b.sealLink(link)
+		if err != nil {
+			return nil, err
+		}
+
+		sealedState.Links[id] = sealedLink
+	}
+
+	return &sealedState, nil
+}
+
+// sealLink returns link with encrypted tokens, reusing the sealed values of unchanged tokens.
+func (b *EncryptedBackend) sealLink(link UserLink) (UserLink, error) {
+	cached, ok := b.sealed[link.TelegramID]
+	if !ok || cached.token != link.TaigaToken || cached.refresh != link.TaigaRefresh {
+		token, err := b.cipher.Seal(link.TaigaToken)
+		if err != nil {
+			return link, fmt.Errorf("не вдалося зашифрувати токен: %w", err)
+		}
+
+		refresh, err := b.cipher.Seal(link.TaigaRefresh)
+		if err != nil {
+			return link, fmt.Errorf("не вдалося зашифрувати токен: %w", err)
+		}
+
+		cached = sealedTokens{token: link.TaigaToken, refresh: link.TaigaRefresh, sealedToken: token, sealedRefresh: refresh}
+		b.sealed[link.TelegramID] = cached
+	}
+
+	link.TaigaToken = cached.sealedToken
+	link.TaigaRefresh = cached.sealedRefresh
+
+	return link, nil
+}
@@ -0,0 +1,268 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testTokenKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, TokenKeySize)
+}
+
+func TestTokenCipher_SealOpen(t *testing.T) {
+	t.Parallel()
+
+	oldCipher, err := NewTokenCipher(testTokenKey(1))
+	if err != nil {
+		t.Fatalf("NewTokenCipher: %v", err)
+	}
+
+	sealed, err := oldCipher.Seal("secret-token")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+
+	if !IsSealedToken(sealed) || strings.Contains(sealed, "secret-token") {
+		t.Fatalf("unexpected sealed value: %q", sealed)
+	}
+
+	rotated, err := NewTokenCipher(testTokenKey(2), testTokenKey(1))
+	if err != nil {
+		t.Fatalf("NewTokenCipher: %v", err)
+	}
+
+	plain, current, err := rotated.Open(sealed)
+	if err != nil || plain != "secret-token" || current {
+		t.Fatalf("Open with previous key: %q %v %v", plain, current, err)
+	}
+
+	if plain, current, err := rotated.Open("legacy"); err != nil || plain != "legacy" || current {
+		t.Fatalf("Open plaintext: %q %v %v", plain, current, err)
+	}
+
+	other, _ := NewTokenCipher(testTokenKey(3))
+	if _, _, err := other.Open(sealed); err == nil {
+		t.Fatalf("expected error for an unknown key")
+	}
+
+	if _, err := NewTokenCipher([]byte("short")); err == nil {
+		t.Fatalf("expected error for a short key")
+	}
+}
+
+func TestEncryptedBackend_UpgradeAndRotate(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	plainStore, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := plainStore.Save(UserLink{TelegramID: 1, TaigaToken: "auth-token", TaigaRefresh: "refresh-token"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := plainStore.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	firstCipher, _ := NewTokenCipher(testTokenKey(1))
+
+	// Loading a plaintext store encrypts it right away.
+	st, err := Open(NewEncryptedBackend(NewFileBackend(path), firstCipher))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	assertNoPlaintextTokens(t, path, "auth-token", "refresh-token")
+
+	if link, _ := st.Get(1); link.TaigaToken != "auth-token" || link.TaigaRefresh != "refresh-token" {
+		t.Fatalf("unexpected decrypted link: %+v", link)
+	}
+
+	if err := st.UpdateTokens(1, "refresh-token", "new-auth", "new-refresh"); err != nil {
+		t.Fatalf("UpdateTokens: %v", err)
+	}
+
+	assertNoPlaintextTokens(t, path, "new-auth", "new-refresh")
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := New(path); err == nil {
+		t.Fatalf("expected error opening an encrypted store without a key")
+	}
+
+	// Rotation: the new key reads tokens sealed with the previous one and re-encrypts them.
+	rotatedCipher, _ := NewTokenCipher(testTokenKey(2), testTokenKey(1))
+
+	rotated, err := Open(NewEncryptedBackend(NewFileBackend(path), rotatedCipher))
+	if err != nil {
+		t.Fatalf("Open rotated: %v", err)
+	}
+
+	if err := rotated.Rewrite(); err != nil {
+		t.Fatalf("Rewrite: %v", err)
+	}
+
+	if err := rotated.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	secondCipher, _ := NewTokenCipher(testTokenKey(2))
+
+	reopened, err := Open(NewEncryptedBackend(NewFileBackend(path), secondCipher))
+	if err != nil {
+		t.Fatalf("Open with the new key only: %v", err)
+	}
+
+	if link, _ := reopened.Get(1); link.TaigaToken != "new-auth" || link.TaigaRefresh != "new-refresh" {
+		t.Fatalf("unexpected link after rotation: %+v", link)
+	}
+}
+
+func TestEncryptedBackend_SealsMigrationBackups(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	// A version 0 store leaves two backups with plaintext tokens behind.
+	document := `{"1":{"telegram_id":1,"taiga_token":"auth-token","taiga_refresh":"refresh-token"}}`
+	if err := os.WriteFile(path, []byte(document), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	firstCipher, _ := NewTokenCipher(testTokenKey(1))
+
+	st, err := Open(NewEncryptedBackend(NewFileBackend(path), firstCipher))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	assertNoPlaintextTokens(t, path, "auth-token", "refresh-token")
+
+	// Rotation seals the backups with the new key as well.
+	rotatedCipher, _ := NewTokenCipher(testTokenKey(2), testTokenKey(1))
+
+	rotated, err := Open(NewEncryptedBackend(NewJournalBackend(path), rotatedCipher))
+	if err != nil {
+		t.Fatalf("Open rotated: %v", err)
+	}
+
+	if err := rotated.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	secondCipher, _ := NewTokenCipher(testTokenKey(2))
+
+	raw, err := os.ReadFile(path + ".v0.bak")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	plain, current, err := secondCipher.Open(strings.TrimSpace(string(raw)))
+	if err != nil || !current || plain != document {
+		t.Fatalf("expected the original document sealed with the new key: %q %v %v", plain, current, err)
+	}
+}
+
+// stateRecorder records the state each Commit of a journal receives.
+type stateRecorder struct {
+	*JournalBackend
+	states []*Snapshot
+}
+
+func (r *stateRecorder) Commit(change Change, state *Snapshot) error {
+	r.states = append(r.states, state)
+
+	return r.JournalBackend.Commit(change, state)
+}
+
+func TestEncryptedBackend_SealsStateOnlyForCompaction(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	journal := NewJournalBackend(path)
+	journal.compactEvery = 2
+
+	recorder := &stateRecorder{JournalBackend: journal}
+	tokenCipher, _ := NewTokenCipher(testTokenKey(1))
+
+	st, err := Open(NewEncryptedBackend(recorder, tokenCipher))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	if err := st.Save(UserLink{TelegramID: 1, TaigaToken: "auth-token", TaigaRefresh: "refresh-token"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	raw, err := os.ReadFile(path + ".journal")
+	if err != nil || bytes.Contains(raw, []byte("auth-token")) || !bytes.Contains(raw, []byte(sealedTokenPrefix)) {
+		t.Fatalf("expected a sealed journal record: %s %v", raw, err)
+	}
+
+	if err := st.UpdateTaskState(1, map[int64]TaskDigest{5: {Status: "New"}}); err != nil {
+		t.Fatalf("UpdateTaskState: %v", err)
+	}
+
+	if len(recorder.states) != 2 || recorder.states[0] != nil || recorder.states[1] == nil {
+		t.Fatalf("expected the state only for the compacting commit: %v", recorder.states)
+	}
+
+	assertNoPlaintextTokens(t, path, "auth-token", "refresh-token")
+}
+
+// assertNoPlaintextTokens checks the store file at path and every migration backup next to it.
+func assertNoPlaintextTokens(t *testing.T, path string, tokens ...string) {
+	t.Helper()
+
+	backups, err := filepath.Glob(path + ".v*.bak")
+	if err != nil {
+		t.Fatalf("Glob: %v", err)
+	}
+
+	for _, file := range append([]string{path}, backups...) {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+
+		for _, token := range tokens {
+			if bytes.Contains(raw, []byte(token)) {
+				t.Fatalf("token %q stored in plaintext in %s", token, filepath.Base(file))
+			}
+		}
+
+		if !bytes.Contains(raw, []byte(sealedTokenPrefix)) {
+			t.Fatalf("expected sealed tokens in %s: %s", filepath.Base(file), raw)
+		}
+	}
+}
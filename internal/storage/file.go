@@ -0,0 +1,208 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrLocked is returned when another process already uses the store.
+var ErrLocked = errors.New("сховище вже використовує інший процес taigagra")
+
+// FileBackend keeps the whole state in one JSON file that is rewritten on every change.
+type FileBackend struct {
+	lock *os.File
+	path string
+}
+
+// NewFileBackend returns a backend storing the state in the JSON file at path.
+func NewFileBackend(path string) *FileBackend {
+	return &FileBackend{path: path}
+}
+
+// Load locks the store against other processes and reads the JSON file,
+// upgrading documents of older schema versions.
+func (b *FileBackend) Load() (Snapshot, error) {
+	lock, err := lockStore(b.path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, err := loadSnapshot(b.path)
+	if err != nil {
+		lock.Close()
+		return Snapshot{}, err
+	}
+
+	b.lock = lock
+
+	return snapshot, nil
+}
+
+// Commit rewrites the JSON file with the whole state.
+func (b *FileBackend) Commit(_ Change, state *Snapshot) error {
+	return writeSnapshot(b.path, state)
+}
+
+// Rewrite writes state to the JSON file.
+func (b *FileBackend) Rewrite(state *Snapshot) error {
+	return writeSnapshot(b.path, state)
+}
+
+// Close releases the lock on the store; every change is already on disk.
+func (b *FileBackend) Close() error {
+	return unlockStore(&b.lock)
+}
+
+// unlockStore releases a lock taken by lockStore, if any.
+func unlockStore(lock **os.File) error {
+	if *lock == nil {
+		return nil
+	}
+
+	err := (*lock).Close()
+	*lock = nil
+
+	return err
+}
+
+// storePath returns the path of the store file, next to which migrations leave their backups.
+func (b *FileBackend) storePath() string {
+	return b.path
+}
+
+// loadSnapshot reads the store document at path, migrating and rewriting it first
+// if it was written with an older schema.
+func loadSnapshot(path string) (Snapshot, error) {
+	var snapshot Snapshot
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshot, nil
+		}
+
+		return snapshot, fmt.Errorf("не вдалося прочитати сховище: %w", err)
+	}
+
+	raw, migrated, err := migrateDocument(path, raw)
+	if err != nil {
+		return snapshot, err
+	}
+
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("не вдалося прочитати сховище: %w", err)
+	}
+
+	if migrated {
+		if err := writeSnapshot(path, &snapshot); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// writeSnapshot replaces the file at path with state through a temporary file.
+// The data is synced before the rename and the directory after it, so a crash
+// leaves either the old or the new file, never an empty one.
+func writeSnapshot(path string, state *Snapshot) error {
+	tmpFile := path + ".tmp"
+
+	file, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	data := Snapshot{SchemaVersion: SchemaVersion, Links: state.Links}
+	if len(state.ProjectUserMappings) > 0 {
+		data.ProjectUserMappings = state.ProjectUserMappings
+	}
+
+	if len(state.TelegramUsernames) > 0 {
+		data.TelegramUsernames = state.TelegramUsernames
+	}
+
+	if len(state.NotificationTargets) > 0 {
+		data.NotificationTargets = state.NotificationTargets
+	}
+
+	if len(state.ChatProjects) > 0 {
+		data.ChatProjects = state.ChatProjects
+	}
+
+	if err := encoder.Encode(data); err != nil {
+		file.Close()
+		return fmt.Errorf("не вдалося записати сховище: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("не вдалося записати сховище: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		return err
+	}
+
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("не вдалося записати сховище: %w", err)
+	}
+
+	return nil
+}
+
+// writeFileSynced replaces the file at path with data the way writeSnapshot does:
+// through a synced temporary file, followed by a sync of the directory.
+func writeFileSynced(path string, data []byte) error {
+	tmpFile := path + ".tmp"
+
+	file, err := os.OpenFile(tmpFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpFile, path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
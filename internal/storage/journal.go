@@ -0,0 +1,245 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// defaultJournalCompactEvery is how many journaled changes trigger a compaction.
+const defaultJournalCompactEvery = 1000
+
+// JournalBackend appends every change as one JSON line to a journal next to a snapshot
+// and, every so many changes, compacts the journal into the snapshot.
+// The snapshot is a store file in the FileBackend format, so an existing JSON
+// store is migrated by opening its path with a JournalBackend.
+type JournalBackend struct {
+	file         journalFile
+	lock         *os.File
+	failed       error
+	path         string
+	size         int64
+	records      int
+	compactEvery int
+}
+
+// journalFile is the open journal; tests replace it to inject write failures.
+type journalFile interface {
+	io.WriteCloser
+	Sync() error
+	Truncate(size int64) error
+}
+
+// NewJournalBackend returns a backend keeping its snapshot at path and its journal at path+".journal".
+func NewJournalBackend(path string) *JournalBackend {
+	return &JournalBackend{path: path, compactEvery: defaultJournalCompactEvery}
+}
+
+// storePath returns the path of the snapshot, next to which migrations leave their backups.
+func (b *JournalBackend) storePath() string {
+	return b.path
+}
+
+func (b *JournalBackend) journalPath() string {
+	return b.path + ".journal"
+}
+
+// Load reads the snapshot, replays the journal over it and compacts the result.
+// A torn last line, left by a crash in the middle of an append, is dropped.
+func (b *JournalBackend) Load() (Snapshot, error) {
+	lock, err := lockStore(b.path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot, err := b.load()
+	if err != nil {
+		lock.Close()
+		b.Close()
+
+		return Snapshot{}, err
+	}
+
+	b.lock = lock
+
+	return snapshot, nil
+}
+
+func (b *JournalBackend) load() (Snapshot, error) {
+	snapshot, err := loadSnapshot(b.path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot.init()
+
+	file, err := os.OpenFile(b.journalPath(), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("не вдалося відкрити журнал сховища: %w", err)
+	}
+
+	// A newly created journal must survive a crash along with the records synced into it.
+	if err := syncDir(filepath.Dir(b.path)); err != nil {
+		file.Close()
+		return Snapshot{}, fmt.Errorf("не вдалося відкрити журнал сховища: %w", err)
+	}
+
+	var (
+		reader = bufio.NewReader(file)
+		valid  int64
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			file.Close()
+			return Snapshot{}, fmt.Errorf("не вдалося прочитати журнал сховища: %w", err)
+		}
+
+		var change Change
+		if err := json.Unmarshal(bytes.TrimSpace(line), &change); err != nil {
+			file.Close()
+			return Snapshot{}, fmt.Errorf("журнал сховища пошкоджено на зсуві %d: %w", valid, err)
+		}
+
+		change.apply(&snapshot)
+
+		valid += int64(len(line))
+		b.records++
+	}
+
+	if err := file.Truncate(valid); err != nil {
+		file.Close()
+		return Snapshot{}, fmt.Errorf("не вдалося обрізати журнал сховища: %w", err)
+	}
+
+	b.file = file
+	b.size = valid
+
+	if b.records > 0 {
+		if err := b.compact(&snapshot); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// commitNeedsState reports whether the next Commit compacts and so reads its state.
+func (b *JournalBackend) commitNeedsState() bool {
+	return b.records+1 >= b.compactEvery
+}
+
+// Commit appends change to the journal and syncs it to disk. state is only read
+// when the commit triggers a compaction, see commitNeedsState.
+func (b *JournalBackend) Commit(change Change, state *Snapshot) error {
+	if b.file == nil {
+		return errors.New("журнал сховища не відкрито")
+	}
+
+	if b.failed != nil {
+		return fmt.Errorf("журнал сховища недоступний після збою запису: %w", b.failed)
+	}
+
+	line, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("не вдалося записати сховище: %w", err)
+	}
+
+	line = append(line, '\n')
+
+	_, err = b.file.Write(line)
+	if err == nil {
+		err = b.file.Sync()
+	}
+
+	if err != nil {
+		// A partial line left mid-file would make the next Load reject the whole journal.
+		if truncErr := b.file.Truncate(b.size); truncErr != nil {
+			b.failed = truncErr
+			return fmt.Errorf("не вдалося записати журнал сховища: %w", errors.Join(err, truncErr))
+		}
+
+		return fmt.Errorf("не вдалося записати журнал сховища: %w", err)
+	}
+
+	b.size += int64(len(line))
+	b.records++
+	if b.records < b.compactEvery {
+		return nil
+	}
+
+	return b.compact(state)
+}
+
+// Rewrite writes state as the new snapshot and empties the journal.
+func (b *JournalBackend) Rewrite(state *Snapshot) error {
+	if b.file == nil {
+		return errors.New("журнал сховища не відкрито")
+	}
+
+	if b.failed != nil {
+		return fmt.Errorf("журнал сховища недоступний після збою запису: %w", b.failed)
+	}
+
+	return b.compact(state)
+}
+
+// compact writes state as the new snapshot and empties the journal.
+// A crash between the two leaves changes that are already in the snapshot in
+// the journal; replaying them again on load is harmless.
+func (b *JournalBackend) compact(state *Snapshot) error {
+	if err := writeSnapshot(b.path, state); err != nil {
+		return err
+	}
+
+	if err := b.file.Truncate(0); err != nil {
+		return fmt.Errorf("не вдалося обрізати журнал сховища: %w", err)
+	}
+
+	if err := b.file.Sync(); err != nil {
+		return fmt.Errorf("не вдалося обрізати журнал сховища: %w", err)
+	}
+
+	b.size = 0
+	b.records = 0
+
+	return nil
+}
+
+// Close closes the journal and releases the lock on the store.
+// Changes still in the journal are compacted by the next Load.
+func (b *JournalBackend) Close() error {
+	var err error
+
+	if b.file != nil {
+		err = b.file.Close()
+		b.file = nil
+	}
+
+	return errors.Join(err, unlockStore(&b.lock))
+}
@@ -0,0 +1,251 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalBackend_ReplayAndMigration(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	// A store written in the JSON file format is picked up by the journal.
+	jsonStore, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := jsonStore.Save(UserLink{TelegramID: 1, TaigaToken: "t", TaigaUserID: 10}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := jsonStore.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st, err := Open(NewJournalBackend(path))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if _, ok := st.Get(1); !ok {
+		t.Fatalf("expected migrated link")
+	}
+
+	if err := st.UpdateTaskState(1, map[int64]TaskDigest{5: {Status: "New"}}); err != nil {
+		t.Fatalf("UpdateTaskState: %v", err)
+	}
+
+	if err := st.SetIssueDigest(1, 7, &TaskDigest{Status: "Open", Version: 2}); err != nil {
+		t.Fatalf("SetIssueDigest: %v", err)
+	}
+
+	if err := st.UpsertTelegramUsername("@Alice", 1); err != nil {
+		t.Fatalf("UpsertTelegramUsername: %v", err)
+	}
+
+	if err := st.SetChatProject(-100, ChatProject{ProjectID: 3, Slug: "p"}); err != nil {
+		t.Fatalf("SetChatProject: %v", err)
+	}
+
+	if err := st.Delete(2); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The changes live only in the journal until the next load compacts them.
+	if info, err := os.Stat(path + ".journal"); err != nil || info.Size() == 0 {
+		t.Fatalf("expected journaled changes: %v", err)
+	}
+
+	// A crash in the middle of an append leaves a torn last line.
+	journal, err := os.OpenFile(path+".journal", os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatalf("open journal: %v", err)
+	}
+
+	_, _ = journal.WriteString(`{"op":"delete_link","telegram_id":1`)
+	_ = journal.Close()
+
+	reopened, err := Open(NewJournalBackend(path))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+
+	link, ok := reopened.Get(1)
+	if !ok {
+		t.Fatalf("expected link after replay")
+	}
+
+	if link.LastTaskStates[5].Status != "New" || link.LastIssueStates[7].Version != 2 {
+		t.Fatalf("unexpected digests: %+v %+v", link.LastTaskStates, link.LastIssueStates)
+	}
+
+	if id, ok := reopened.ResolveTelegramHandle("alice"); !ok || id != 1 {
+		t.Fatalf("unexpected username: %d %v", id, ok)
+	}
+
+	if project, ok := reopened.GetChatProject(-100); !ok || project.ProjectID != 3 {
+		t.Fatalf("unexpected chat project: %+v %v", project, ok)
+	}
+
+	if info, err := os.Stat(path + ".journal"); err != nil || info.Size() != 0 {
+		t.Fatalf("expected journal compacted on load: %v", err)
+	}
+
+	if err := reopened.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The compacted snapshot stays readable by the JSON file backend.
+	snapshot, err := New(path)
+	if err != nil {
+		t.Fatalf("New after compaction: %v", err)
+	}
+
+	if link, ok := snapshot.Get(1); !ok || link.LastIssueStates[7].Status != "Open" {
+		t.Fatalf("unexpected snapshot link: %+v %v", link, ok)
+	}
+}
+
+func TestJournalBackend_CompactsPeriodically(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	backend := NewJournalBackend(path)
+	backend.compactEvery = 3
+
+	st, err := Open(backend)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	if err := st.Save(UserLink{TelegramID: 1}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	for i, status := range []string{"New", "Done"} {
+		if err := st.UpdateTaskState(1, map[int64]TaskDigest{5: {Status: status}}); err != nil {
+			t.Fatalf("UpdateTaskState %d: %v", i, err)
+		}
+	}
+
+	if info, err := os.Stat(path + ".journal"); err != nil || info.Size() != 0 {
+		t.Fatalf("expected journal compacted after 3 changes: %v", err)
+	}
+
+	// An unchanged digest map is not journaled again.
+	if err := st.UpdateTaskState(1, map[int64]TaskDigest{5: {Status: "Done"}}); err != nil {
+		t.Fatalf("UpdateTaskState: %v", err)
+	}
+
+	if info, err := os.Stat(path + ".journal"); err != nil || info.Size() != 0 {
+		t.Fatalf("expected no journal record for unchanged digests: %v", err)
+	}
+
+	snapshot, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatalf("loadSnapshot: %v", err)
+	}
+
+	if snapshot.Links[1].LastTaskStates[5].Status != "Done" {
+		t.Fatalf("unexpected snapshot: %+v", snapshot.Links[1])
+	}
+}
+
+// shortWriteFile writes half of the next line to the journal and then fails.
+type shortWriteFile struct {
+	*os.File
+	fail bool
+}
+
+func (f *shortWriteFile) Write(p []byte) (int, error) {
+	if !f.fail {
+		return f.File.Write(p)
+	}
+
+	f.fail = false
+
+	n, _ := f.File.Write(p[:len(p)/2])
+
+	return n, errors.New("disk full")
+}
+
+func TestJournalBackend_TruncatesFailedWrite(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	backend := NewJournalBackend(path)
+
+	st, err := Open(backend)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+
+	if err := st.Save(UserLink{TelegramID: 1}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	file, ok := backend.file.(*os.File)
+	if !ok {
+		t.Fatalf("unexpected journal file %T", backend.file)
+	}
+
+	failing := &shortWriteFile{File: file, fail: true}
+	backend.file = failing
+
+	if err := st.Save(UserLink{TelegramID: 2}); err == nil {
+		t.Fatalf("expected failed write to be reported")
+	}
+
+	// The journal keeps accepting changes after the torn line is cut off.
+	if err := st.Save(UserLink{TelegramID: 3}); err != nil {
+		t.Fatalf("Save after failed write: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := Open(NewJournalBackend(path))
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+
+	if _, ok := reopened.Get(1); !ok {
+		t.Fatalf("expected link written before the failure")
+	}
+
+	if _, ok := reopened.Get(2); ok {
+		t.Fatalf("expected failed write to leave no link")
+	}
+
+	if _, ok := reopened.Get(3); !ok {
+		t.Fatalf("expected link written after the failure")
+	}
+}
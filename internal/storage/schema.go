@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SchemaVersion is the version of the store document written by this build.
+//
+// Versions:
+//
+//	0: a bare map of links keyed by Telegram id.
+//	1: a document with "links" and optional sections, without schema_version.
+//	2: version 1 with schema_version.
+const SchemaVersion = 2
+
+// migration upgrades a store document from version from to version from+1.
+type migration struct {
+	migrate func(raw []byte) ([]byte, error)
+	from    int
+}
+
+// migrations run in order; each one must move the document exactly one version up.
+var migrations = []migration{
+	{from: 0, migrate: migrateLegacyLinks},
+	{from: 1, migrate: migrateSchemaVersion},
+}
+
+// documentVersion tells the version of a store document. Documents from before
+// schema_version existed are told apart by the "links" key.
+func documentVersion(raw []byte) (int, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return 0, fmt.Errorf("не вдалося прочитати сховище: %w", err)
+	}
+
+	if rawVersion, ok := doc["schema_version"]; ok {
+		var version int
+		if err := json.Unmarshal(rawVersion, &version); err != nil {
+			return 0, fmt.Errorf("некоректна версія схеми сховища: %w", err)
+		}
+
+		return version, nil
+	}
+
+	if _, ok := doc["links"]; ok {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// migrateDocument upgrades the document read from path to SchemaVersion. Before each
+// migration the document is backed up to <path>.v<version>.bak. It reports whether
+// any migration ran.
+//
+// Backups are written as they are, tokens included. With a token key configured,
+// EncryptedBackend seals every backup as a whole right after loading, and seals it
+// again with the current key after a rotation; see EncryptedBackend.sealBackups.
+func migrateDocument(path string, raw []byte) ([]byte, bool, error) {
+	version, err := documentVersion(raw)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if version > SchemaVersion {
+		return nil, false, fmt.Errorf("сховище має версію схеми %d, новішу за підтримувану %d", version, SchemaVersion)
+	}
+
+	migrated := false
+
+	for _, m := range migrations {
+		if m.from != version {
+			continue
+		}
+
+		backup := fmt.Sprintf("%s.v%d.bak", path, version)
+		if err := writeFileSynced(backup, raw); err != nil {
+			return nil, false, fmt.Errorf("не вдалося зберегти резервну копію сховища: %w", err)
+		}
+
+		raw, err = m.migrate(raw)
+		if err != nil {
+			return nil, false, fmt.Errorf("не вдалося оновити сховище з версії %d: %w", version, err)
+		}
+
+		version++
+		migrated = true
+	}
+
+	if version != SchemaVersion {
+		return nil, false, fmt.Errorf("немає міграції сховища з версії %d", version)
+	}
+
+	return raw, migrated, nil
+}
+
+// migrateLegacyLinks wraps the bare map of links of version 0 into a document.
+func migrateLegacyLinks(raw []byte) ([]byte, error) {
+	var links map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &links); err != nil {
+		return nil, err
+	}
+
+	if links == nil {
+		links = make(map[string]json.RawMessage)
+	}
+
+	return json.Marshal(map[string]any{"links": links})
+}
+
+// migrateSchemaVersion stamps a version 1 document with its schema version.
+func migrateSchemaVersion(raw []byte) ([]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	doc["schema_version"] = json.RawMessage("2")
+
+	return json.Marshal(doc)
+}
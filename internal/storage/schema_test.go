@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStore_MigratesHistoricalFormats(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		document string
+		backups  []int
+		sections bool
+	}{
+		{
+			name:     "version 0: bare map of links",
+			document: `{"123":{"telegram_id":123,"taiga_token":"t","taiga_user_id":456,"taiga_user_name":"name","last_task_states":{}}}`,
+			backups:  []int{0, 1},
+		},
+		{
+			name: "version 1: document without schema_version",
+			document: `{"links":{"123":{"telegram_id":123,"taiga_token":"t","taiga_user_id":456,"taiga_user_name":"name","last_task_states":{"5":{"status":"New","assigned_to":0}}}},` +
+				`"project_user_mappings":{"1":{"123":456}},"telegram_usernames":{"alice":123},` +
+				`"notification_targets":{"-100":{"7":{"kind":"task","item_id":9,"ref":3}}},"chat_projects":{"-100":{"slug":"p","project_id":1}}}`,
+			backups:  []int{1},
+			sections: true,
+		},
+		{
+			name: "version 2: current",
+			document: `{"schema_version":2,"links":{"123":{"telegram_id":123,"taiga_token":"t","taiga_user_id":456,"taiga_user_name":"name","last_task_states":{}}},` +
+				`"project_user_mappings":{"1":{"123":456}},"telegram_usernames":{"alice":123},` +
+				`"notification_targets":{"-100":{"7":{"kind":"task","item_id":9,"ref":3}}},"chat_projects":{"-100":{"slug":"p","project_id":1}}}`,
+			sections: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "store.json")
+			if err := os.WriteFile(path, []byte(tt.document), 0o600); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			st, err := New(path)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+
+			link, ok := st.Get(123)
+			if !ok || link.TaigaUserID != 456 || link.TaigaToken != "t" {
+				t.Fatalf("unexpected link: %+v %v", link, ok)
+			}
+
+			if tt.sections {
+				if id, ok := st.GetProjectUserMapping(1, 123); !ok || id != 456 {
+					t.Fatalf("unexpected project user mapping: %d %v", id, ok)
+				}
+
+				if id, ok := st.ResolveTelegramHandle("alice"); !ok || id != 123 {
+					t.Fatalf("unexpected username: %d %v", id, ok)
+				}
+
+				if target, ok := st.GetNotificationTarget(-100, 7); !ok || target.ItemID != 9 {
+					t.Fatalf("unexpected notification target: %+v %v", target, ok)
+				}
+
+				if project, ok := st.GetChatProject(-100); !ok || project.Slug != "p" {
+					t.Fatalf("unexpected chat project: %+v %v", project, ok)
+				}
+			}
+
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+
+			if version, err := documentVersion(raw); err != nil || version != SchemaVersion {
+				t.Fatalf("expected the file upgraded to version %d, got %d (%v)", SchemaVersion, version, err)
+			}
+
+			for version := range SchemaVersion {
+				_, err := os.Stat(fmt.Sprintf("%s.v%d.bak", path, version))
+				if wantBackup := slices.Contains(tt.backups, version); wantBackup != (err == nil) {
+					t.Fatalf("backup of version %d: want=%v err=%v", version, wantBackup, err)
+				}
+			}
+
+			if len(tt.backups) > 0 {
+				first, err := os.ReadFile(fmt.Sprintf("%s.v%d.bak", path, tt.backups[0]))
+				if err != nil || string(first) != tt.document {
+					t.Fatalf("expected the original document backed up: %q %v", first, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_RejectsNewerSchema(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	if err := os.WriteFile(path, []byte(`{"schema_version":99,"links":{}}`), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := New(path); err == nil {
+		t.Fatalf("expected error for a newer schema version")
+	}
+}
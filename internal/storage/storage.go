@@ -16,11 +16,10 @@
 package storage
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"os"
+	"maps"
+	"slices"
 	"strings"
 	"sync"
 )
@@ -29,49 +28,104 @@ import (
 type UserLink struct {
 	NotifyChatID    *int64               `json:"notify_chat_id,omitempty"`
 	LastTaskStates  map[int64]TaskDigest `json:"last_task_states"`
+	LastIssueStates map[int64]TaskDigest `json:"last_issue_states,omitempty"`
 	TaigaToken      string               `json:"taiga_token"`
 	TaigaRefresh    string               `json:"taiga_refresh,omitempty"`
+	TaigaTokenType  string               `json:"taiga_token_type,omitempty"`
 	TaigaUserName   string               `json:"taiga_user_name"`
 	WatchedProjects []int64              `json:"watched_projects,omitempty"`
 	TelegramID      int64                `json:"telegram_id"`
 	TaigaUserID     int64                `json:"taiga_user_id"`
+	PollingDisabled bool                 `json:"polling_disabled,omitempty"`
+	// RefreshExpiryWarned is set once the user was told their refresh token is about to expire.
+	RefreshExpiryWarned bool `json:"refresh_expiry_warned,omitempty"`
 }
 
+// TokenTypeApplication marks a link whose TaigaToken is a Taiga application token
+// rather than a refreshable auth token.
+const TokenTypeApplication = "application"
+
 // TaskDigest captures key fields to detect changes between polling cycles.
 type TaskDigest struct {
-	Status     string `json:"status"`
-	AssignedTo int64  `json:"assigned_to"`
+	Status string `json:"status"`
+	// LastHistoryID is the newest history entry already reported for the item.
+	LastHistoryID string `json:"last_history_id,omitempty"`
+	AssignedTo    int64  `json:"assigned_to"`
+	ProjectID     int64  `json:"project_id,omitempty"`
+	// Version is Taiga's item version; it changes with every edit and comment.
+	Version int64 `json:"version,omitempty"`
+}
+
+// NotificationTarget points a sent Telegram notification at the Taiga item it describes.
+type NotificationTarget struct {
+	Kind      string `json:"kind"`
+	ItemID    int64  `json:"item_id"`
+	Ref       int64  `json:"ref"`
+	ProjectID int64  `json:"project_id,omitempty"`
+}
+
+// ChatProject is the Taiga project a chat is bound to, so bare #ref lookups resolve against it.
+type ChatProject struct {
+	Slug      string `json:"slug"`
+	ProjectID int64  `json:"project_id"`
 }
 
+// maxNotificationTargetsPerChat bounds how many notifications per chat stay answerable by reply.
+const maxNotificationTargetsPerChat = 1000
+
 // Store persists user links.
 type Store struct {
-	links               map[int64]UserLink
-	projectUserMappings map[int64]map[int64]int64
-	telegramUsernames   map[string]int64
-	path                string
-	mu                  sync.Mutex
+	backend Backend
+	state   Snapshot
+	mu      sync.Mutex
 }
 
-type diskData struct {
-	Links               map[int64]UserLink        `json:"links"`
-	ProjectUserMappings map[int64]map[int64]int64 `json:"project_user_mappings,omitempty"`
-	TelegramUsernames   map[string]int64          `json:"telegram_usernames,omitempty"`
+// New creates or loads a store kept in a single JSON file.
+func New(path string) (*Store, error) {
+	return Open(NewFileBackend(path))
 }
 
-// New creates or loads a store from disk.
-func New(path string) (*Store, error) {
-	store := &Store{
-		path:                path,
-		links:               make(map[int64]UserLink),
-		projectUserMappings: make(map[int64]map[int64]int64),
-		telegramUsernames:   make(map[string]int64),
-	}
-	err := store.load()
+// Open creates or loads a store persisted by backend.
+func Open(backend Backend) (*Store, error) {
+	state, err := backend.Load()
 	if err != nil {
 		return nil, err
 	}
 
-	return store, nil
+	state.init()
+
+	for id, link := range state.Links {
+		if IsSealedToken(link.TaigaToken) || IsSealedToken(link.TaigaRefresh) {
+			backend.Close()
+
+			return nil, fmt.Errorf("токени користувача %d зашифровані: потрібен ключ шифрування токенів", id)
+		}
+	}
+
+	return &Store{backend: backend, state: state}, nil
+}
+
+// Rewrite stores the whole state again, e.g. to re-encrypt tokens with a new key.
+func (s *Store) Rewrite() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.backend.Rewrite(&s.state)
+}
+
+// Close releases the backend of the store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.backend.Close()
+}
+
+// commit applies change to the in-memory state and hands it to the backend.
+func (s *Store) commit(change Change) error {
+	change.apply(&s.state)
+
+	return s.backend.Commit(change, &s.state)
 }
 
 // Get returns the link for a telegram user.
@@ -79,23 +133,18 @@ func (s *Store) Get(telegramID int64) (UserLink, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	link, ok := s.links[telegramID]
+	link, ok := s.state.Links[telegramID]
 
 	return link, ok
 }
 
 // Save inserts or updates a link.
 func (s *Store) Save(link UserLink) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return s.Transaction(func(tx *Tx) error {
+		tx.Save(link)
 
-	if link.LastTaskStates == nil {
-		link.LastTaskStates = make(map[int64]TaskDigest)
-	}
-
-	s.links[link.TelegramID] = link
-
-	return s.persist()
+		return nil
+	})
 }
 
 // Delete removes a link.
@@ -103,69 +152,120 @@ func (s *Store) Delete(telegramID int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.links, telegramID)
-
-	return s.persist()
+	return s.commit(Change{Op: OpDeleteLink, TelegramID: telegramID})
 }
 
 // UpdateTaskState replaces the stored digest map for a user.
 func (s *Store) UpdateTaskState(telegramID int64, digests map[int64]TaskDigest) error {
+	return s.setStates(telegramID, digests, OpSetTaskStates, func(link UserLink) map[int64]TaskDigest { return link.LastTaskStates })
+}
+
+// UpdateIssueState replaces the stored issue digest map for a user.
+func (s *Store) UpdateIssueState(telegramID int64, digests map[int64]TaskDigest) error {
+	return s.setStates(telegramID, digests, OpSetIssueStates, func(link UserLink) map[int64]TaskDigest { return link.LastIssueStates })
+}
+
+// setStates replaces a digest map of a user. Polling stores the digests on every
+// cycle, so an unchanged map is not written again.
+func (s *Store) setStates(telegramID int64, digests map[int64]TaskDigest, op ChangeOp, states func(UserLink) map[int64]TaskDigest) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	link, ok := s.links[telegramID]
+	link, ok := s.state.Links[telegramID]
 	if !ok {
 		return fmt.Errorf("користувач %d не привʼязаний", telegramID)
 	}
 
-	link.LastTaskStates = digests
-	s.links[telegramID] = link
+	if current := states(link); current != nil && maps.Equal(current, digests) {
+		return nil
+	}
 
-	return s.persist()
+	return s.commit(Change{Op: op, TelegramID: telegramID, Digests: digests})
 }
 
-func (s *Store) SetNotifyChat(telegramID int64, chatID *int64) error {
+// SetTaskDigest records or, with a nil digest, forgets the state of one user story of a user.
+func (s *Store) SetTaskDigest(telegramID, itemID int64, digest *TaskDigest) error {
+	return s.setDigest(telegramID, itemID, digest, OpSetTaskDigest, func(link UserLink) map[int64]TaskDigest { return link.LastTaskStates })
+}
+
+// SetIssueDigest records or, with a nil digest, forgets the state of one issue of a user.
+func (s *Store) SetIssueDigest(telegramID, itemID int64, digest *TaskDigest) error {
+	return s.setDigest(telegramID, itemID, digest, OpSetIssueDigest, func(link UserLink) map[int64]TaskDigest { return link.LastIssueStates })
+}
+
+func (s *Store) setDigest(telegramID, itemID int64, digest *TaskDigest, op ChangeOp, states func(UserLink) map[int64]TaskDigest) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	link, ok := s.links[telegramID]
+	link, ok := s.state.Links[telegramID]
 	if !ok {
 		return fmt.Errorf("користувач %d не привʼязаний", telegramID)
 	}
 
-	link.NotifyChatID = chatID
-	s.links[telegramID] = link
+	if digest == nil {
+		if _, ok := states(link)[itemID]; !ok {
+			return nil
+		}
+	}
 
-	return s.persist()
+	return s.commit(Change{Op: op, TelegramID: telegramID, ItemID: itemID, Digest: digest})
 }
 
-func (s *Store) SetProjectUserMapping(projectID, telegramID, taigaUserID int64) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// ErrTokensReplaced is returned by UpdateTokens when the link no longer holds the refresh
+// token the refresh started from, e.g. because the user linked new tokens meanwhile.
+var ErrTokensReplaced = errors.New("токени користувача вже замінено")
+
+// UpdateTokens stores refreshed Taiga tokens without touching the rest of the link,
+// so a refresh cannot undo settings changed since the link was read. The tokens are
+// only stored while the link still holds previousRefresh, the refresh token they replace.
+func (s *Store) UpdateTokens(telegramID int64, previousRefresh, authToken, refreshToken string) error {
+	return s.Update(telegramID, func(link *UserLink) error {
+		if link.TaigaRefresh != previousRefresh || link.TaigaTokenType == TokenTypeApplication {
+			return ErrTokensReplaced
+		}
 
-	if projectID <= 0 {
-		return errors.New("некоректний id проєкту")
-	}
+		if link.TaigaRefresh != refreshToken {
+			link.RefreshExpiryWarned = false
+		}
 
-	if telegramID == 0 {
-		return errors.New("некоректний id користувача Telegram")
-	}
+		link.TaigaToken = authToken
+		link.TaigaRefresh = refreshToken
 
-	if taigaUserID <= 0 {
-		return errors.New("некоректний id користувача Taiga")
-	}
+		return nil
+	})
+}
 
-	if s.projectUserMappings == nil {
-		s.projectUserMappings = make(map[int64]map[int64]int64)
-	}
+// SetPollingDisabled pauses or resumes background Taiga requests for a user,
+// e.g. after Taiga stopped accepting their tokens.
+func (s *Store) SetPollingDisabled(telegramID int64, disabled bool) error {
+	return s.Update(telegramID, func(link *UserLink) error {
+		link.PollingDisabled = disabled
 
-	if s.projectUserMappings[projectID] == nil {
-		s.projectUserMappings[projectID] = make(map[int64]int64)
-	}
+		return nil
+	})
+}
 
-	s.projectUserMappings[projectID][telegramID] = taigaUserID
+// SetRefreshExpiryWarned records whether the user was warned about their expiring refresh token.
+func (s *Store) SetRefreshExpiryWarned(telegramID int64, warned bool) error {
+	return s.Update(telegramID, func(link *UserLink) error {
+		link.RefreshExpiryWarned = warned
 
-	return s.persist()
+		return nil
+	})
+}
+
+func (s *Store) SetNotifyChat(telegramID int64, chatID *int64) error {
+	return s.Update(telegramID, func(link *UserLink) error {
+		link.NotifyChatID = chatID
+
+		return nil
+	})
+}
+
+func (s *Store) SetProjectUserMapping(projectID, telegramID, taigaUserID int64) error {
+	return s.Transaction(func(tx *Tx) error {
+		return tx.SetProjectUserMapping(projectID, telegramID, taigaUserID)
+	})
 }
 
 func (s *Store) RemoveProjectUserMapping(projectID, telegramID int64) error {
@@ -180,32 +280,18 @@ func (s *Store) RemoveProjectUserMapping(projectID, telegramID int64) error {
 		return errors.New("некоректний id користувача Telegram")
 	}
 
-	if s.projectUserMappings == nil {
-		return nil
-	}
-
-	if s.projectUserMappings[projectID] == nil {
+	if _, ok := s.state.ProjectUserMappings[projectID][telegramID]; !ok {
 		return nil
 	}
 
-	delete(s.projectUserMappings[projectID], telegramID)
-
-	if len(s.projectUserMappings[projectID]) == 0 {
-		delete(s.projectUserMappings, projectID)
-	}
-
-	return s.persist()
+	return s.commit(Change{Op: OpRemoveProjectUserMapping, ProjectID: projectID, TelegramID: telegramID})
 }
 
 func (s *Store) GetProjectUserMapping(projectID, telegramID int64) (int64, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.projectUserMappings == nil {
-		return 0, false
-	}
-
-	m, ok := s.projectUserMappings[projectID]
+	m, ok := s.state.ProjectUserMappings[projectID]
 	if !ok {
 		return 0, false
 	}
@@ -220,11 +306,8 @@ func (s *Store) ListProjectUserMappings(projectID int64) map[int64]int64 {
 	defer s.mu.Unlock()
 
 	result := make(map[int64]int64)
-	if s.projectUserMappings == nil {
-		return result
-	}
 
-	m, ok := s.projectUserMappings[projectID]
+	m, ok := s.state.ProjectUserMappings[projectID]
 	if !ok {
 		return result
 	}
@@ -248,17 +331,11 @@ func (s *Store) UpsertTelegramUsername(username string, telegramID int64) error
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.telegramUsernames == nil {
-		s.telegramUsernames = make(map[string]int64)
-	}
-
-	if existing, ok := s.telegramUsernames[username]; ok && existing == telegramID {
+	if existing, ok := s.state.TelegramUsernames[username]; ok && existing == telegramID {
 		return nil
 	}
 
-	s.telegramUsernames[username] = telegramID
-
-	return s.persist()
+	return s.commit(Change{Op: OpSetTelegramUsername, Username: username, TelegramID: telegramID})
 }
 
 func (s *Store) ResolveTelegramHandle(handle string) (int64, bool) {
@@ -273,142 +350,101 @@ func (s *Store) ResolveTelegramHandle(handle string) (int64, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.telegramUsernames == nil {
-		return 0, false
-	}
-
-	id, ok := s.telegramUsernames[handle]
+	id, ok := s.state.TelegramUsernames[handle]
 
 	return id, ok
 }
 
-// AddWatchedProject subscribes a telegram user to a Taiga project.
-func (s *Store) AddWatchedProject(telegramID, projectID int64) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	link, ok := s.links[telegramID]
-	if !ok {
-		return fmt.Errorf("користувач %d не привʼязаний", telegramID)
+// SaveNotificationTarget remembers which Taiga item a sent notification message refers to.
+// Only the newest maxNotificationTargetsPerChat messages of each chat are kept.
+func (s *Store) SaveNotificationTarget(chatID int64, messageID int, target NotificationTarget) error {
+	if messageID <= 0 {
+		return errors.New("некоректний id повідомлення")
 	}
 
-	for _, existing := range link.WatchedProjects {
-		if existing == projectID {
-			s.links[telegramID] = link
-			return s.persist()
-		}
+	if target.ItemID <= 0 {
+		return errors.New("некоректний id обʼєкта Taiga")
 	}
 
-	link.WatchedProjects = append(link.WatchedProjects, projectID)
-	s.links[telegramID] = link
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return s.persist()
+	return s.commit(Change{Op: OpSetNotificationTarget, ChatID: chatID, MessageID: messageID, NotificationTarget: &target})
 }
 
-// RemoveWatchedProject unsubscribes a telegram user from a Taiga project.
-func (s *Store) RemoveWatchedProject(telegramID, projectID int64) error {
+// GetNotificationTarget returns the Taiga item a notification message refers to.
+func (s *Store) GetNotificationTarget(chatID int64, messageID int) (NotificationTarget, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	link, ok := s.links[telegramID]
-	if !ok {
-		return fmt.Errorf("користувач %d не привʼязаний", telegramID)
-	}
+	target, ok := s.state.NotificationTargets[chatID][messageID]
 
-	filtered := make([]int64, 0, len(link.WatchedProjects))
-	for _, existing := range link.WatchedProjects {
-		if existing != projectID {
-			filtered = append(filtered, existing)
-		}
+	return target, ok
+}
+
+// SetChatProject binds a chat to a Taiga project.
+func (s *Store) SetChatProject(chatID int64, project ChatProject) error {
+	if project.ProjectID <= 0 {
+		return errors.New("некоректний id проєкту")
 	}
 
-	link.WatchedProjects = filtered
-	s.links[telegramID] = link
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return s.persist()
+	return s.commit(Change{Op: OpSetChatProject, ChatID: chatID, ChatProject: &project})
 }
 
-// List returns all stored links.
-func (s *Store) List() []UserLink {
+// ClearChatProject removes the project binding of a chat.
+func (s *Store) ClearChatProject(chatID int64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result := make([]UserLink, 0, len(s.links))
-	for _, link := range s.links {
-		result = append(result, link)
+	if _, ok := s.state.ChatProjects[chatID]; !ok {
+		return nil
 	}
 
-	return result
+	return s.commit(Change{Op: OpSetChatProject, ChatID: chatID})
 }
 
-func (s *Store) load() error {
-	file, err := os.Open(s.path)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
+// GetChatProject returns the project a chat is bound to.
+func (s *Store) GetChatProject(chatID int64) (ChatProject, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		return err
-	}
-	defer file.Close()
+	project, ok := s.state.ChatProjects[chatID]
 
-	raw, err := io.ReadAll(file)
-	if err != nil {
-		return fmt.Errorf("не вдалося прочитати сховище: %w", err)
-	}
-
-	var dd diskData
-	if err := json.Unmarshal(raw, &dd); err == nil && dd.Links != nil {
-		s.links = dd.Links
-		if dd.ProjectUserMappings != nil {
-			s.projectUserMappings = dd.ProjectUserMappings
-		}
+	return project, ok
+}
 
-		if dd.TelegramUsernames != nil {
-			s.telegramUsernames = dd.TelegramUsernames
+// AddWatchedProject subscribes a telegram user to a Taiga project.
+func (s *Store) AddWatchedProject(telegramID, projectID int64) error {
+	return s.Update(telegramID, func(link *UserLink) error {
+		if !slices.Contains(link.WatchedProjects, projectID) {
+			link.WatchedProjects = append(link.WatchedProjects, projectID)
 		}
 
 		return nil
-	}
-
-	var legacy map[int64]UserLink
-	if err := json.Unmarshal(raw, &legacy); err != nil {
-		return fmt.Errorf("не вдалося прочитати сховище: %w", err)
-	}
-
-	s.links = legacy
-
-	return nil
+	})
 }
 
-func (s *Store) persist() error {
-	tmpFile := s.path + ".tmp"
-
-	file, err := os.Create(tmpFile)
-	if err != nil {
-		return err
-	}
-
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-
-	data := diskData{Links: s.links}
-	if len(s.projectUserMappings) > 0 {
-		data.ProjectUserMappings = s.projectUserMappings
-	}
+// RemoveWatchedProject unsubscribes a telegram user from a Taiga project.
+func (s *Store) RemoveWatchedProject(telegramID, projectID int64) error {
+	return s.Update(telegramID, func(link *UserLink) error {
+		link.WatchedProjects = slices.DeleteFunc(link.WatchedProjects, func(existing int64) bool { return existing == projectID })
 
-	if len(s.telegramUsernames) > 0 {
-		data.TelegramUsernames = s.telegramUsernames
-	}
+		return nil
+	})
+}
 
-	if err := encoder.Encode(data); err != nil {
-		file.Close()
-		return fmt.Errorf("не вдалося записати сховище: %w", err)
-	}
+// List returns all stored links.
+func (s *Store) List() []UserLink {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if err := file.Close(); err != nil {
-		return err
+	result := make([]UserLink, 0, len(s.state.Links))
+	for _, link := range s.state.Links {
+		result = append(result, link)
 	}
 
-	return os.Rename(tmpFile, s.path)
+	return result
 }
@@ -17,6 +17,7 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -141,6 +142,10 @@ func TestStore_TelegramUsernameIndex(t *testing.T) {
 		t.Fatalf("unexpected id: got=%d want=%d", got, 123)
 	}
 
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
 	st2, err := New(path)
 	if err != nil {
 		t.Fatalf("New: %v", err)
@@ -155,3 +160,312 @@ func TestStore_TelegramUsernameIndex(t *testing.T) {
 		t.Fatalf("unexpected id after reload: got=%d want=%d", got, 123)
 	}
 }
+
+func TestStore_NotificationTargets(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	target := NotificationTarget{Kind: "userstory", ItemID: 10, Ref: 42, ProjectID: 1}
+	if err := st.SaveNotificationTarget(-100, 5, target); err != nil {
+		t.Fatalf("SaveNotificationTarget: %v", err)
+	}
+
+	if err := st.SaveNotificationTarget(-100, 0, target); err == nil {
+		t.Fatalf("expected error for invalid message id")
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st2, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	got, ok := st2.GetNotificationTarget(-100, 5)
+	if !ok {
+		t.Fatalf("expected target after reload")
+	}
+
+	if got != target {
+		t.Fatalf("unexpected target: got=%+v want=%+v", got, target)
+	}
+
+	if _, ok := st2.GetNotificationTarget(-100, 6); ok {
+		t.Fatalf("unexpected target for unknown message")
+	}
+
+	for i := 1; i <= maxNotificationTargetsPerChat+5; i++ {
+		if err := st2.SaveNotificationTarget(7, i, target); err != nil {
+			t.Fatalf("SaveNotificationTarget: %v", err)
+		}
+	}
+
+	if _, ok := st2.GetNotificationTarget(7, 5); ok {
+		t.Fatalf("expected oldest targets to be evicted")
+	}
+
+	if _, ok := st2.GetNotificationTarget(7, maxNotificationTargetsPerChat+5); !ok {
+		t.Fatalf("expected newest target to be kept")
+	}
+}
+
+func TestStore_ChatProjects(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := st.SetChatProject(-100, ChatProject{ProjectID: 0}); err == nil {
+		t.Fatalf("expected error for invalid project id")
+	}
+
+	project := ChatProject{ProjectID: 3, Slug: "team-board"}
+	if err := st.SetChatProject(-100, project); err != nil {
+		t.Fatalf("SetChatProject: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st2, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	got, ok := st2.GetChatProject(-100)
+	if !ok || got != project {
+		t.Fatalf("unexpected chat project after reload: got=%+v ok=%v", got, ok)
+	}
+
+	if err := st2.ClearChatProject(-100); err != nil {
+		t.Fatalf("ClearChatProject: %v", err)
+	}
+
+	if _, ok := st2.GetChatProject(-100); ok {
+		t.Fatalf("expected binding to be removed")
+	}
+}
+
+func TestStore_SetDigest(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := st.SetTaskDigest(1, 10, &TaskDigest{Status: "New"}); err == nil {
+		t.Fatalf("expected error for unknown user")
+	}
+
+	if err := st.Save(UserLink{TelegramID: 1, TaigaToken: "t", LastTaskStates: map[int64]TaskDigest{9: {Status: "Done"}}}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	before, _ := st.Get(1)
+
+	if err := st.SetTaskDigest(1, 10, &TaskDigest{Status: "New", ProjectID: 3}); err != nil {
+		t.Fatalf("SetTaskDigest: %v", err)
+	}
+
+	if err := st.SetIssueDigest(1, 20, &TaskDigest{Status: "Open", AssignedTo: 5}); err != nil {
+		t.Fatalf("SetIssueDigest: %v", err)
+	}
+
+	if _, ok := before.LastTaskStates[10]; ok {
+		t.Fatalf("previously returned link must not change")
+	}
+
+	if err := st.SetTaskDigest(1, 9, nil); err != nil {
+		t.Fatalf("SetTaskDigest nil: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st2, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	link, _ := st2.Get(1)
+
+	if len(link.LastTaskStates) != 1 || link.LastTaskStates[10] != (TaskDigest{Status: "New", ProjectID: 3}) {
+		t.Fatalf("unexpected task states: %+v", link.LastTaskStates)
+	}
+
+	if link.LastIssueStates[20] != (TaskDigest{Status: "Open", AssignedTo: 5}) {
+		t.Fatalf("unexpected issue states: %+v", link.LastIssueStates)
+	}
+}
+
+func TestStore_SetPollingDisabled(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := st.SetPollingDisabled(1, true); err == nil {
+		t.Fatalf("expected error for unknown user")
+	}
+
+	if err := st.Save(UserLink{TelegramID: 1, TaigaToken: "t"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := st.SetPollingDisabled(1, true); err != nil {
+		t.Fatalf("SetPollingDisabled: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st2, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if link, _ := st2.Get(1); !link.PollingDisabled {
+		t.Fatalf("expected polling to stay disabled after reload")
+	}
+}
+
+func TestStore_UpdateTokens(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := st.UpdateTokens(1, "", "a", "r"); err == nil {
+		t.Fatalf("expected error for unknown user")
+	}
+
+	if err := st.Save(UserLink{TelegramID: 1, TaigaToken: "old", TaigaRefresh: "old-r"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := st.AddWatchedProject(1, 7); err != nil {
+		t.Fatalf("AddWatchedProject: %v", err)
+	}
+
+	if err := st.UpdateTokens(1, "old-r", "new", "new-r"); err != nil {
+		t.Fatalf("UpdateTokens: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st2, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	link, _ := st2.Get(1)
+	if link.TaigaToken != "new" || link.TaigaRefresh != "new-r" {
+		t.Fatalf("unexpected tokens: %q %q", link.TaigaToken, link.TaigaRefresh)
+	}
+
+	if len(link.WatchedProjects) != 1 || link.WatchedProjects[0] != 7 {
+		t.Fatalf("watched projects lost: %v", link.WatchedProjects)
+	}
+
+	if err := st.SetRefreshExpiryWarned(1, true); err != nil {
+		t.Fatalf("SetRefreshExpiryWarned: %v", err)
+	}
+
+	if err := st.UpdateTokens(1, "new-r", "newer", "new-r"); err != nil {
+		t.Fatalf("UpdateTokens: %v", err)
+	}
+
+	if link, _ := st.Get(1); !link.RefreshExpiryWarned {
+		t.Fatalf("warning must survive an auth-only refresh")
+	}
+
+	if err := st.UpdateTokens(1, "new-r", "newest", "rotated-r"); err != nil {
+		t.Fatalf("UpdateTokens: %v", err)
+	}
+
+	if link, _ := st.Get(1); link.RefreshExpiryWarned {
+		t.Fatalf("warning must reset with a new refresh token")
+	}
+
+	// A refresh that started before the user linked an application token must not overwrite it.
+	if err := st.Save(UserLink{TelegramID: 1, TaigaToken: "app", TaigaTokenType: TokenTypeApplication}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if err := st.UpdateTokens(1, "rotated-r", "late", "late-r"); !errors.Is(err, ErrTokensReplaced) {
+		t.Fatalf("expected ErrTokensReplaced, got %v", err)
+	}
+
+	if link, _ := st.Get(1); link.TaigaToken != "app" || link.TaigaTokenType != TokenTypeApplication {
+		t.Fatalf("relinked tokens overwritten: %+v", link)
+	}
+}
+
+func TestStore_SingleInstanceLock(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := New(path)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, err := New(path); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked for a second file store, got %v", err)
+	}
+
+	if _, err := Open(NewJournalBackend(path)); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected ErrLocked for a second journal store, got %v", err)
+	}
+
+	if err := st.Save(UserLink{TelegramID: 1}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected no temporary file left behind: %v", err)
+	}
+
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	st2, err := New(path)
+	if err != nil {
+		t.Fatalf("New after Close: %v", err)
+	}
+	defer st2.Close()
+
+	if _, ok := st2.Get(1); !ok {
+		t.Fatalf("expected link")
+	}
+}
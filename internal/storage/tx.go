@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// Tx collects the changes of one Store.Transaction. It is only valid inside the
+// transaction function, which runs under the store lock and so must not call
+// methods of the Store itself.
+type Tx struct {
+	state *Snapshot
+	// links holds the links written by the transaction; nil marks a deleted one.
+	links   map[int64]*UserLink
+	changes []Change
+}
+
+// Transaction runs fn under the store lock and persists all changes it made at once.
+// If fn returns an error, nothing is changed.
+func (s *Store) Transaction(fn func(tx *Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx := &Tx{state: &s.state, links: make(map[int64]*UserLink)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	switch len(tx.changes) {
+	case 0:
+		return nil
+	case 1:
+		return s.commit(tx.changes[0])
+	default:
+		return s.commit(Change{Op: OpBatch, Changes: tx.changes})
+	}
+}
+
+// Update changes the link of a user under the store lock and persists it once,
+// so concurrent updates of different fields do not undo each other.
+// fn gets a copy it may modify freely, maps and slices included;
+// returning an error discards the change.
+func (s *Store) Update(telegramID int64, fn func(link *UserLink) error) error {
+	return s.Transaction(func(tx *Tx) error {
+		return tx.Update(telegramID, fn)
+	})
+}
+
+// Get returns the link of a user as the transaction has left it so far.
+func (tx *Tx) Get(telegramID int64) (UserLink, bool) {
+	if link, ok := tx.links[telegramID]; ok {
+		if link == nil {
+			return UserLink{}, false
+		}
+
+		return *link, true
+	}
+
+	link, ok := tx.state.Links[telegramID]
+
+	return link, ok
+}
+
+// Update changes the link of a user like Store.Update. An update that leaves
+// the link as it was is not persisted.
+func (tx *Tx) Update(telegramID int64, fn func(link *UserLink) error) error {
+	current, ok := tx.Get(telegramID)
+	if !ok {
+		return fmt.Errorf("користувач %d не привʼязаний", telegramID)
+	}
+
+	link := current.clone()
+	if err := fn(&link); err != nil {
+		return err
+	}
+
+	if link.TelegramID != telegramID {
+		return errors.New("не можна змінити id користувача Telegram у привʼязці")
+	}
+
+	if reflect.DeepEqual(link, current) {
+		return nil
+	}
+
+	tx.Save(link)
+
+	return nil
+}
+
+// Save inserts or replaces a link.
+func (tx *Tx) Save(link UserLink) {
+	if link.LastTaskStates == nil {
+		link.LastTaskStates = make(map[int64]TaskDigest)
+	}
+
+	tx.links[link.TelegramID] = &link
+	tx.changes = append(tx.changes, Change{Op: OpPutLink, Link: &link})
+}
+
+// Delete removes a link.
+func (tx *Tx) Delete(telegramID int64) {
+	tx.links[telegramID] = nil
+	tx.changes = append(tx.changes, Change{Op: OpDeleteLink, TelegramID: telegramID})
+}
+
+// SetTaskStates replaces the user story digests of a user like Store.UpdateTaskState,
+// recording only that field rather than the whole link.
+func (tx *Tx) SetTaskStates(telegramID int64, digests map[int64]TaskDigest) error {
+	return tx.setStates(telegramID, digests, OpSetTaskStates)
+}
+
+// SetIssueStates replaces the issue digests of a user like Store.UpdateIssueState.
+func (tx *Tx) SetIssueStates(telegramID int64, digests map[int64]TaskDigest) error {
+	return tx.setStates(telegramID, digests, OpSetIssueStates)
+}
+
+func (tx *Tx) setStates(telegramID int64, digests map[int64]TaskDigest, op ChangeOp) error {
+	current, ok := tx.Get(telegramID)
+	if !ok {
+		return fmt.Errorf("користувач %d не привʼязаний", telegramID)
+	}
+
+	link := current.clone()
+
+	states := &link.LastTaskStates
+	if op == OpSetIssueStates {
+		states = &link.LastIssueStates
+	}
+
+	if *states != nil && maps.Equal(*states, digests) {
+		return nil
+	}
+
+	digests = maps.Clone(digests)
+	*states = digests
+
+	tx.links[telegramID] = &link
+	tx.changes = append(tx.changes, Change{Op: op, TelegramID: telegramID, Digests: digests})
+
+	return nil
+}
+
+// SetProjectUserMapping maps a Telegram user to a Taiga user within a project.
+func (tx *Tx) SetProjectUserMapping(projectID, telegramID, taigaUserID int64) error {
+	if projectID <= 0 {
+		return errors.New("некоректний id проєкту")
+	}
+
+	if telegramID == 0 {
+		return errors.New("некоректний id користувача Telegram")
+	}
+
+	if taigaUserID <= 0 {
+		return errors.New("некоректний id користувача Taiga")
+	}
+
+	tx.changes = append(tx.changes, Change{Op: OpSetProjectUserMapping, ProjectID: projectID, TelegramID: telegramID, TaigaUserID: taigaUserID})
+
+	return nil
+}
+
+// clone returns a copy of link that shares no maps, slices or pointers with it.
+func (l UserLink) clone() UserLink {
+	l.LastTaskStates = maps.Clone(l.LastTaskStates)
+	l.LastIssueStates = maps.Clone(l.LastIssueStates)
+	l.WatchedProjects = slices.Clone(l.WatchedProjects)
+
+	if l.NotifyChatID != nil {
+		chatID := *l.NotifyChatID
+		l.NotifyChatID = &chatID
+	}
+
+	return l
+}
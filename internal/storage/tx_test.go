@@ -0,0 +1,202 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// TestStore_ConcurrentUpdates is meant to run under go test -race.
+func TestStore_ConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+
+	st, err := New(filepath.Join(t.TempDir(), "store.json"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if err := st.Save(UserLink{TelegramID: 1, TaigaToken: "t0", TaigaRefresh: "r"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	const workers = 16
+
+	var wg sync.WaitGroup
+
+	for i := range workers {
+		projectID := int64(i + 1)
+
+		wg.Add(4)
+
+		go func() {
+			defer wg.Done()
+
+			if err := st.AddWatchedProject(1, projectID); err != nil {
+				t.Errorf("AddWatchedProject: %v", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			if err := st.SetTaskDigest(1, projectID, &TaskDigest{Status: "New"}); err != nil {
+				t.Errorf("SetTaskDigest: %v", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			err := st.Update(1, func(link *UserLink) error {
+				if link.LastIssueStates == nil {
+					link.LastIssueStates = make(map[int64]TaskDigest)
+				}
+
+				link.LastIssueStates[projectID] = TaskDigest{Status: "Open"}
+
+				return nil
+			})
+			if err != nil {
+				t.Errorf("Update: %v", err)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			// Readers walk the maps of their copies while writers update the store.
+			link, _ := st.Get(1)
+			for range link.LastTaskStates {
+			}
+
+			for range link.LastIssueStates {
+			}
+
+			_ = st.UpdateTokens(1, "r", "t", "r")
+		}()
+	}
+
+	wg.Wait()
+
+	link, ok := st.Get(1)
+	if !ok {
+		t.Fatalf("expected link")
+	}
+
+	if len(link.WatchedProjects) != workers || len(link.LastTaskStates) != workers || len(link.LastIssueStates) != workers {
+		t.Fatalf("lost updates: watched=%d tasks=%d issues=%d", len(link.WatchedProjects), len(link.LastTaskStates), len(link.LastIssueStates))
+	}
+
+	if link.TaigaToken != "t" || link.TaigaRefresh != "r" {
+		t.Fatalf("lost token update: %+v", link)
+	}
+}
+
+func TestStore_Transaction(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	st, err := Open(NewJournalBackend(path))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer st.Close()
+
+	errAbort := errors.New("abort")
+
+	err = st.Transaction(func(tx *Tx) error {
+		tx.Save(UserLink{TelegramID: 1})
+
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+
+	if _, ok := st.Get(1); ok {
+		t.Fatalf("expected aborted transaction to change nothing")
+	}
+
+	err = st.Transaction(func(tx *Tx) error {
+		tx.Save(UserLink{TelegramID: 1, TaigaUserID: 10})
+
+		if err := tx.Update(1, func(link *UserLink) error {
+			link.WatchedProjects = []int64{3}
+			return nil
+		}); err != nil {
+			return err
+		}
+
+		return tx.SetProjectUserMapping(3, 1, 10)
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	if link, ok := st.Get(1); !ok || !slices.Equal(link.WatchedProjects, []int64{3}) {
+		t.Fatalf("unexpected link: %+v %v", link, ok)
+	}
+
+	if id, ok := st.GetProjectUserMapping(3, 1); !ok || id != 10 {
+		t.Fatalf("unexpected mapping: %d %v", id, ok)
+	}
+
+	raw, err := os.ReadFile(path + ".journal")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	if lines := bytes.Count(raw, []byte("\n")); lines != 1 {
+		t.Fatalf("expected the transaction persisted once, got %d records", lines)
+	}
+
+	if err := st.Update(2, func(*UserLink) error { return nil }); err == nil {
+		t.Fatalf("expected error updating a missing link")
+	}
+
+	// Digest maps are recorded on their own, without the tokens and the rest of the link.
+	err = st.Transaction(func(tx *Tx) error {
+		if err := tx.SetTaskStates(1, map[int64]TaskDigest{5: {Status: "New"}}); err != nil {
+			return err
+		}
+
+		return tx.SetIssueStates(1, map[int64]TaskDigest{7: {Status: "Open"}})
+	})
+	if err != nil {
+		t.Fatalf("Transaction: %v", err)
+	}
+
+	if link, _ := st.Get(1); link.LastTaskStates[5].Status != "New" || link.LastIssueStates[7].Status != "Open" || link.TaigaUserID != 10 {
+		t.Fatalf("unexpected link after digest transaction: %+v", link)
+	}
+
+	raw, err = os.ReadFile(path + ".journal")
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	last := raw[bytes.LastIndexByte(raw[:len(raw)-1], '\n')+1:]
+	if !bytes.Contains(last, []byte(OpSetTaskStates)) || !bytes.Contains(last, []byte(OpSetIssueStates)) || bytes.Contains(last, []byte(OpPutLink)) {
+		t.Fatalf("expected per-field digest records, got %s", last)
+	}
+}
@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxAttachmentSize bounds uploads read into memory; Telegram bots cannot download larger files anyway.
+const maxAttachmentSize = 50 * 1024 * 1024
+
+// multipartPayload is a request body sent as multipart/form-data instead of JSON.
+// The file is kept in memory so the request can be replayed after a token refresh.
+type multipartPayload struct {
+	fields    map[string]string
+	fileField string
+	fileName  string
+	file      []byte
+}
+
+func (p *multipartPayload) encode() ([]byte, string, error) {
+	var buf bytes.Buffer
+
+	w := multipart.NewWriter(&buf)
+
+	for name, value := range p.fields {
+		if err := w.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if p.fileField != "" {
+		part, err := w.CreateFormFile(p.fileField, p.fileName)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if _, err := part.Write(p.file); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// AttachmentUpload describes a file attached to a user story, task or issue.
+type AttachmentUpload struct {
+	Content     io.Reader
+	FileName    string
+	Description string
+	ProjectID   int64
+	ObjectID    int64
+}
+
+// Attachment represents a Taiga attachment subset used by the bot.
+type Attachment struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	ID       int64  `json:"id"`
+	ObjectID int64  `json:"object_id"`
+	Project  int64  `json:"project"`
+	Size     int64  `json:"size"`
+}
+
+// CreateAttachment uploads a file to a user story, task or issue.
+func (c *Client) CreateAttachment(ctx context.Context, kind ItemKind, upload AttachmentUpload) (Attachment, error) {
+	var attachment Attachment
+
+	collection, err := kind.collection()
+	if err != nil {
+		return attachment, err
+	}
+
+	if upload.ProjectID <= 0 || upload.ObjectID <= 0 {
+		return attachment, errors.New("потрібні проєкт і обʼєкт")
+	}
+
+	if upload.Content == nil {
+		return attachment, errors.New("потрібен файл")
+	}
+
+	content, err := io.ReadAll(io.LimitReader(upload.Content, maxAttachmentSize+1))
+	if err != nil {
+		return attachment, fmt.Errorf("не вдалося прочитати файл: %w", err)
+	}
+
+	if len(content) > maxAttachmentSize {
+		return attachment, fmt.Errorf("файл завеликий: понад %d байт", maxAttachmentSize)
+	}
+
+	fileName := strings.TrimSpace(path.Base(upload.FileName))
+	if fileName == "" || fileName == "." || fileName == "/" {
+		fileName = "attachment"
+	}
+
+	payload := &multipartPayload{
+		fields: map[string]string{
+			"project":   strconv.FormatInt(upload.ProjectID, 10),
+			"object_id": strconv.FormatInt(upload.ObjectID, 10),
+		},
+		fileField: "attached_file",
+		fileName:  fileName,
+		file:      content,
+	}
+
+	if description := strings.TrimSpace(upload.Description); description != "" {
+		payload.fields["description"] = description
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: collection + "/attachments"})
+	if err := c.do(ctx, http.MethodPost, endpoint.String(), payload, &attachment); err != nil {
+		return attachment, err
+	}
+
+	return attachment, nil
+}
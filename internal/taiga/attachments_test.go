@@ -0,0 +1,133 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_CreateAttachment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unknown_kind", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewClient("https://example.com/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		_, err = c.CreateAttachment(t.Context(), ItemKind("wiki"), AttachmentUpload{ProjectID: 1, ObjectID: 1, Content: strings.NewReader("x")})
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("multipart_replayed_after_refresh", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 2)
+
+		var uploads int
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/api/v1/auth/refresh":
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]string{"auth_token": "new-auth", "refresh": "new-refresh"})
+			case "/api/v1/userstories/attachments":
+				uploads++
+				if uploads == 1 {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					errCh <- fmt.Errorf("parse multipart: %w", err)
+
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+
+				if r.FormValue("project") != "1" || r.FormValue("object_id") != "9" || r.FormValue("description") != "screenshot" {
+					errCh <- fmt.Errorf("unexpected fields: %v", r.MultipartForm.Value)
+
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+
+				file, header, err := r.FormFile("attached_file")
+				if err != nil {
+					errCh <- fmt.Errorf("form file: %w", err)
+
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+				defer file.Close()
+
+				content, _ := io.ReadAll(file)
+				if header.Filename != "shot.png" || string(content) != "png-bytes" {
+					errCh <- fmt.Errorf("unexpected file: %s %q", header.Filename, content)
+
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(Attachment{ID: 3, Name: "shot.png", ObjectID: 9, Project: 1, Size: int64(len(content))})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer srv.Close()
+
+		c, err := NewClientWithTokens(srv.URL+"/api/v1", "old-auth", "old-refresh", nil)
+		if err != nil {
+			t.Fatalf("NewClientWithTokens: %v", err)
+		}
+
+		got, err := c.CreateAttachment(t.Context(), KindUserStory, AttachmentUpload{
+			ProjectID:   1,
+			ObjectID:    9,
+			FileName:    "shot.png",
+			Description: "screenshot",
+			Content:     strings.NewReader("png-bytes"),
+		})
+		if err != nil {
+			t.Fatalf("CreateAttachment: %v", err)
+		}
+
+		select {
+		case err := <-errCh:
+			t.Fatalf("server assertion failed: %v", err)
+		default:
+		}
+
+		if got.ID != 3 || got.Size != int64(len("png-bytes")) {
+			t.Fatalf("unexpected attachment: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,241 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AuthResponse is what Taiga returns for a successful login.
+type AuthResponse struct {
+	AuthToken string `json:"auth_token"`
+	Refresh   string `json:"refresh"`
+	FullName  string `json:"full_name_display"`
+	ID        int64  `json:"id"`
+}
+
+// Login signs in with a Taiga username or email and password.
+func (c *Client) Login(ctx context.Context, username, password string) (AuthResponse, error) {
+	var auth AuthResponse
+	if strings.TrimSpace(username) == "" || password == "" {
+		return auth, errors.New("потрібні логін і пароль")
+	}
+
+	payload := struct {
+		Type     string `json:"type"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}{
+		Type:     "normal",
+		Username: strings.TrimSpace(username),
+		Password: password,
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "auth"})
+	err := c.do(ctx, http.MethodPost, endpoint.String(), payload, &auth)
+	if err != nil {
+		return auth, err
+	}
+
+	if strings.TrimSpace(auth.AuthToken) == "" {
+		return auth, errors.New("Taiga не повернула auth_token")
+	}
+
+	return auth, nil
+}
+
+// AuthorizeApplication lets an external application act on behalf of the
+// authenticated user and returns the authorization code to validate.
+func (c *Client) AuthorizeApplication(ctx context.Context, applicationID, state string) (string, error) {
+	if strings.TrimSpace(applicationID) == "" {
+		return "", errors.New("потрібен id застосунку")
+	}
+
+	payload := struct {
+		Application string `json:"application"`
+		State       string `json:"state"`
+	}{
+		Application: applicationID,
+		State:       state,
+	}
+
+	var out struct {
+		AuthCode string `json:"auth_code"`
+		State    string `json:"state"`
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "application-tokens/authorize"})
+	err := c.do(ctx, http.MethodPost, endpoint.String(), payload, &out)
+	if err != nil {
+		return "", err
+	}
+
+	if out.State != state {
+		return "", errors.New("Taiga повернула інший state авторизації")
+	}
+
+	if out.AuthCode == "" {
+		return "", errors.New("Taiga не повернула код авторизації")
+	}
+
+	return out.AuthCode, nil
+}
+
+// ValidateApplication exchanges an authorization code for the application token,
+// which Taiga sends encrypted with the application key.
+func (c *Client) ValidateApplication(ctx context.Context, applicationID, applicationKey, authCode, state string) (string, error) {
+	payload := struct {
+		Application string `json:"application"`
+		AuthCode    string `json:"auth_code"`
+		State       string `json:"state"`
+	}{
+		Application: applicationID,
+		AuthCode:    authCode,
+		State:       state,
+	}
+
+	var out struct {
+		CypheredToken string `json:"cyphered_token"`
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "application-tokens/validate"})
+	err := c.do(ctx, http.MethodPost, endpoint.String(), payload, &out)
+	if err != nil {
+		return "", err
+	}
+
+	return DecryptApplicationToken(out.CypheredToken, applicationKey)
+}
+
+// DecryptApplicationToken opens the compact JWE (A128KW, A256GCM) in which Taiga
+// delivers application tokens. The wrapping key is derived from the application
+// key the same way Taiga does: the first 16 bytes of its SHA-256.
+func DecryptApplicationToken(cyphered, applicationKey string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(cyphered), ".")
+	if len(parts) != 5 {
+		return "", errors.New("некоректний зашифрований токен застосунку")
+	}
+
+	decoded := make([][]byte, len(parts))
+	for i, part := range parts {
+		raw, err := base64.RawURLEncoding.DecodeString(part)
+		if err != nil {
+			return "", fmt.Errorf("некоректний зашифрований токен застосунку: %w", err)
+		}
+
+		decoded[i] = raw
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Enc string `json:"enc"`
+	}
+	if err := json.Unmarshal(decoded[0], &header); err != nil {
+		return "", fmt.Errorf("некоректний заголовок токена застосунку: %w", err)
+	}
+
+	if header.Alg != "A128KW" || header.Enc != "A256GCM" {
+		return "", fmt.Errorf("непідтримуване шифрування токена застосунку: %s/%s", header.Alg, header.Enc)
+	}
+
+	sum := sha256.Sum256([]byte(applicationKey))
+
+	contentKey, err := aesKeyUnwrap(sum[:16], decoded[1])
+	if err != nil {
+		return "", err
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return "", fmt.Errorf("некоректний ключ вмісту: %w", err)
+	}
+
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(decoded[2]))
+	if err != nil {
+		return "", fmt.Errorf("некоректний ключ вмісту: %w", err)
+	}
+
+	sealed := append(decoded[3], decoded[4]...)
+
+	plain, err := gcm.Open(nil, decoded[2], sealed, []byte(parts[0]))
+	if err != nil {
+		return "", errors.New("не вдалося розшифрувати токен застосунку: перевір ключ застосунку")
+	}
+
+	var content struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(plain, &content); err != nil || content.Token == "" {
+		return "", errors.New("розшифрований токен застосунку не містить token")
+	}
+
+	return content.Token, nil
+}
+
+// aesKeyUnwrapIV is the integrity check value of RFC 3394.
+var aesKeyUnwrapIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// aesKeyUnwrap implements the AES key unwrap of RFC 3394.
+func aesKeyUnwrap(kek, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, errors.New("некоректний зашифрований ключ вмісту")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+
+	a := make([]byte, 8)
+	copy(a, wrapped[:8])
+
+	r := make([]byte, n*8)
+	copy(r, wrapped[8:])
+
+	buf := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(buf[:8], binary.BigEndian.Uint64(a)^t)
+			copy(buf[8:], r[(i-1)*8:i*8])
+
+			block.Decrypt(buf, buf)
+
+			copy(a, buf[:8])
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(a, aesKeyUnwrapIV) != 1 {
+		return nil, errors.New("не вдалося розшифрувати ключ вмісту: перевір ключ застосунку")
+	}
+
+	return r, nil
+}
@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// encryptApplicationToken builds the JWE Taiga sends from /application-tokens/validate.
+func encryptApplicationToken(t *testing.T, token, applicationKey string) string {
+	t.Helper()
+
+	contentKey := make([]byte, 32)
+	for i := range contentKey {
+		contentKey[i] = byte(i)
+	}
+
+	sum := sha256.Sum256([]byte(applicationKey))
+
+	block, err := aes.NewCipher(sum[:16])
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+
+	// RFC 3394 key wrap.
+	n := len(contentKey) / 8
+	a := append([]byte(nil), aesKeyUnwrapIV...)
+	r := append([]byte(nil), contentKey...)
+	buf := make([]byte, 16)
+	for j := range 6 {
+		for i := 1; i <= n; i++ {
+			copy(buf[:8], a)
+			copy(buf[8:], r[(i-1)*8:i*8])
+			block.Encrypt(buf, buf)
+			binary.BigEndian.PutUint64(a, binary.BigEndian.Uint64(buf[:8])^uint64(n*j+i))
+			copy(r[(i-1)*8:i*8], buf[8:])
+		}
+	}
+
+	wrapped := append(a, r...)
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"A128KW","enc":"A256GCM"}`))
+
+	contentBlock, err := aes.NewCipher(contentKey)
+	if err != nil {
+		t.Fatalf("NewCipher: %v", err)
+	}
+
+	gcm, err := cipher.NewGCM(contentBlock)
+	if err != nil {
+		t.Fatalf("NewGCM: %v", err)
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	plain, _ := json.Marshal(map[string]string{"token": token})
+	sealed := gcm.Seal(nil, iv, plain, []byte(header))
+	tagStart := len(sealed) - gcm.Overhead()
+
+	enc := base64.RawURLEncoding.EncodeToString
+
+	return header + "." + enc(wrapped) + "." + enc(iv) + "." + enc(sealed[:tagStart]) + "." + enc(sealed[tagStart:])
+}
+
+func TestAESKeyUnwrap_RFC3394Vector(t *testing.T) {
+	t.Parallel()
+
+	kek, _ := hex.DecodeString("000102030405060708090A0B0C0D0E0F")
+	wrapped, _ := hex.DecodeString("1FA68B0A8112B447AEF34BD8FB5A7B829D3E862371D2CFE5")
+
+	got, err := aesKeyUnwrap(kek, wrapped)
+	if err != nil {
+		t.Fatalf("aesKeyUnwrap: %v", err)
+	}
+
+	if hex.EncodeToString(got) != "00112233445566778899aabbccddeeff" {
+		t.Fatalf("unexpected key: %x", got)
+	}
+}
+
+func TestDecryptApplicationToken(t *testing.T) {
+	t.Parallel()
+
+	cyphered := encryptApplicationToken(t, "app-token", "app-key")
+
+	got, err := DecryptApplicationToken(cyphered, "app-key")
+	if err != nil {
+		t.Fatalf("DecryptApplicationToken: %v", err)
+	}
+
+	if got != "app-token" {
+		t.Fatalf("unexpected token: %q", got)
+	}
+
+	if _, err := DecryptApplicationToken(cyphered, "other-key"); err == nil {
+		t.Fatalf("expected error for a wrong key")
+	}
+}
+
+func TestClient_LoginAndApplicationToken(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/auth":
+			if body["type"] != "normal" || body["username"] != "alice" || body["password"] != "secret" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"_error_message":"Username or password does not matches user."}`))
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(AuthResponse{AuthToken: "auth", Refresh: "refresh", FullName: "Alice", ID: 5})
+		case "/api/v1/application-tokens/authorize":
+			if r.Header.Get("Authorization") != "Bearer auth" || body["application"] != "app-id" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]string{"auth_code": "code", "state": body["state"]})
+		case "/api/v1/application-tokens/validate":
+			if body["auth_code"] != "code" || body["state"] != "xyz" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]string{"cyphered_token": encryptApplicationToken(t, "app-token", "app-key")})
+		case "/api/v1/users/me":
+			if r.Header.Get("Authorization") != "Application app-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(User{ID: 5, FullName: "Alice"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	anonymous, err := NewClient(srv.URL+"/api/v1", "")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := anonymous.Login(context.Background(), "alice", "wrong"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+
+	auth, err := anonymous.Login(context.Background(), "alice", "secret")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+
+	if auth.AuthToken != "auth" || auth.Refresh != "refresh" || auth.ID != 5 {
+		t.Fatalf("unexpected auth: %+v", auth)
+	}
+
+	c, err := NewClientWithTokens(srv.URL+"/api/v1", auth.AuthToken, auth.Refresh, nil)
+	if err != nil {
+		t.Fatalf("NewClientWithTokens: %v", err)
+	}
+
+	code, err := c.AuthorizeApplication(context.Background(), "app-id", "xyz")
+	if err != nil {
+		t.Fatalf("AuthorizeApplication: %v", err)
+	}
+
+	token, err := c.ValidateApplication(context.Background(), "app-id", "app-key", code, "xyz")
+	if err != nil {
+		t.Fatalf("ValidateApplication: %v", err)
+	}
+
+	app, err := NewClientWithTokenSource(srv.URL+"/api/v1", NewApplicationTokenSource(token))
+	if err != nil {
+		t.Fatalf("NewClientWithTokenSource: %v", err)
+	}
+
+	if me, err := app.GetMe(context.Background()); err != nil || me.ID != 5 {
+		t.Fatalf("GetMe with application token: %+v %v", me, err)
+	}
+}
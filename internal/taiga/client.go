@@ -22,19 +22,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Client provides minimal Taiga API interactions required by the bot.
 type Client struct {
 	baseURL    *url.URL
 	httpClient *http.Client
-	authToken  string
-	refresh    string
-	onRefresh  func(authToken, refreshToken string)
+	tokens     *TokenSource
+	metadata   *MetadataCache
+	retry      RetryPolicy
+	// metadataUser is the Taiga user whose entries of a shared metadata cache the client uses.
+	metadataUser int64
 }
 
 // CreateUserStory creates a new user story in Taiga.
@@ -53,12 +57,46 @@ func (c *Client) CreateUserStory(ctx context.Context, req UserStoryCreateRequest
 	return us, nil
 }
 
+// GetUserStoryByRef fetches a user story by its project reference number.
+func (c *Client) GetUserStoryByRef(ctx context.Context, projectID, ref int64) (UserStory, error) {
+	var us UserStory
+	if projectID <= 0 || ref <= 0 {
+		return us, errors.New("потрібні проєкт і номер завдання")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "userstories/by_ref"})
+
+	query := endpoint.Query()
+	query.Set("project", strconv.FormatInt(projectID, 10))
+	query.Set("ref", strconv.FormatInt(ref, 10))
+
+	endpoint.RawQuery = query.Encode()
+
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &us)
+	if err != nil {
+		return us, err
+	}
+
+	return us, nil
+}
+
 // NewClient returns a configured Taiga API client.
 func NewClient(baseURL, authToken string) (*Client, error) {
 	return NewClientWithTokens(baseURL, authToken, "", nil)
 }
 
+// NewClientWithTokens returns a client that refreshes its auth token on 401 responses.
+// onTokensRefreshed, if set, receives the new tokens after every refresh.
 func NewClientWithTokens(baseURL, authToken, refreshToken string, onTokensRefreshed func(authToken, refreshToken string)) (*Client, error) {
+	return NewClientWithTokenSource(baseURL, NewTokenSource(authToken, refreshToken, onTokensRefreshed))
+}
+
+// NewClientWithTokenSource returns a client that takes its tokens from a shared source.
+func NewClientWithTokenSource(baseURL string, tokens *TokenSource) (*Client, error) {
+	if tokens == nil {
+		return nil, errors.New("потрібне джерело токенів")
+	}
+
 	parsed, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("некоректний базовий URL Taiga: %w", err)
@@ -74,10 +112,10 @@ func NewClientWithTokens(baseURL, authToken, refreshToken string, onTokensRefres
 
 	return &Client{
 		baseURL:    parsed,
-		authToken:  authToken,
-		refresh:    refreshToken,
-		onRefresh:  onTokensRefreshed,
+		tokens:     tokens,
 		httpClient: &http.Client{},
+		metadata:   NewMetadataCache(DefaultMetadataTTL),
+		retry:      DefaultRetryPolicy,
 	}, nil
 }
 
@@ -96,6 +134,47 @@ type StatusExtraInfo struct {
 	Name string `json:"name"`
 }
 
+// Tags holds tag names. Taiga sends tags either as plain names
+// or as [name, color] pairs depending on the endpoint.
+type Tags []string
+
+// UnmarshalJSON accepts both tag encodings and drops colors.
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	tags := make(Tags, 0, len(raw))
+
+	for _, item := range raw {
+		item = bytes.TrimSpace(item)
+		if len(item) > 0 && item[0] == '[' {
+			var pair []*string
+			if err := json.Unmarshal(item, &pair); err != nil {
+				return err
+			}
+
+			if len(pair) > 0 && pair[0] != nil {
+				tags = append(tags, *pair[0])
+			}
+
+			continue
+		}
+
+		var name string
+		if err := json.Unmarshal(item, &name); err != nil {
+			return err
+		}
+
+		tags = append(tags, name)
+	}
+
+	*t = tags
+
+	return nil
+}
+
 // UserStoryCreateRequest represents payload accepted by Taiga for user story creation.
 type UserStoryCreateRequest struct {
 	StatusID    *int64   `json:"status,omitempty"`
@@ -107,21 +186,55 @@ type UserStoryCreateRequest struct {
 }
 
 // UserStory represents a Taiga user story subset used by the bot.
+// Description is only sent by the detail endpoints, not by lists.
 type UserStory struct {
-	AssignedTo      *int64          `json:"assigned_to"`
-	Subject         string          `json:"subject"`
-	StatusExtraInfo StatusExtraInfo `json:"status_extra_info"`
-	ID              int64           `json:"id"`
-	Ref             int64           `json:"ref"`
+	AssignedTo       *int64          `json:"assigned_to"`
+	Owner            *int64          `json:"owner"`
+	Milestone        *int64          `json:"milestone"`
+	Subject          string          `json:"subject"`
+	Description      string          `json:"description"`
+	MilestoneName    string          `json:"milestone_name"`
+	DueDate          string          `json:"due_date"`
+	StatusExtraInfo  StatusExtraInfo `json:"status_extra_info"`
+	ProjectExtraInfo Project         `json:"project_extra_info"`
+	AssignedUsers    []int64         `json:"assigned_users"`
+	Watchers         []int64         `json:"watchers"`
+	Epics            []UserStoryEpic `json:"epics"`
+	Tags             Tags            `json:"tags"`
+	TotalPoints      Points          `json:"total_points"`
+	ID               int64           `json:"id"`
+	Ref              int64           `json:"ref"`
+	Project          int64           `json:"project"`
+	Version          int64           `json:"version"`
+	IsClosed         bool            `json:"is_closed"`
+}
+
+// UserStoryEpic is the summary of a related epic embedded in user story lists.
+type UserStoryEpic struct {
+	Subject string `json:"subject"`
+	ID      int64  `json:"id"`
+	Ref     int64  `json:"ref"`
 }
 
 // Task represents a Taiga task subset used by the bot.
+// Description is only sent by the detail endpoints, not by lists.
 type Task struct {
-	AssignedTo      *int64          `json:"assigned_to"`
-	Subject         string          `json:"subject"`
-	StatusExtraInfo StatusExtraInfo `json:"status_extra_info"`
-	ID              int64           `json:"id"`
-	Ref             int64           `json:"ref"`
+	AssignedTo       *int64          `json:"assigned_to"`
+	Owner            *int64          `json:"owner"`
+	Milestone        *int64          `json:"milestone"`
+	UserStory        *int64          `json:"user_story"`
+	Subject          string          `json:"subject"`
+	Description      string          `json:"description"`
+	DueDate          string          `json:"due_date"`
+	StatusExtraInfo  StatusExtraInfo `json:"status_extra_info"`
+	ProjectExtraInfo Project         `json:"project_extra_info"`
+	Watchers         []int64         `json:"watchers"`
+	Tags             Tags            `json:"tags"`
+	ID               int64           `json:"id"`
+	Ref              int64           `json:"ref"`
+	Project          int64           `json:"project"`
+	Version          int64           `json:"version"`
+	IsClosed         bool            `json:"is_closed"`
 }
 
 // User represents Taiga user minimal fields.
@@ -162,6 +275,38 @@ func (c *Client) CreateTask(ctx context.Context, req TaskCreateRequest) (Task, e
 	return task, nil
 }
 
+// GetUserStory fetches user story by id.
+func (c *Client) GetUserStory(ctx context.Context, id int64) (UserStory, error) {
+	var us UserStory
+	if id <= 0 {
+		return us, errors.New("некоректний id завдання")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("userstories/%d", id)})
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &us)
+	if err != nil {
+		return us, err
+	}
+
+	return us, nil
+}
+
+// GetTask fetches task by id.
+func (c *Client) GetTask(ctx context.Context, id int64) (Task, error) {
+	var task Task
+	if id <= 0 {
+		return task, errors.New("некоректний id задачі")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("tasks/%d", id)})
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &task)
+	if err != nil {
+		return task, err
+	}
+
+	return task, nil
+}
+
 // GetUser fetches user by id.
 func (c *Client) GetUser(ctx context.Context, id int64) (User, error) {
 	var user User
@@ -195,8 +340,17 @@ type ListTasksParams struct {
 	ProjectID  int64
 }
 
-// ListTasks fetches tasks using optional filters.
+// ListTasks fetches tasks using optional filters, following every result page.
 func (c *Client) ListTasks(ctx context.Context, params ListTasksParams) ([]Task, error) {
+	return listAll[Task](ctx, c, c.tasksURL(params))
+}
+
+// IterTasks iterates over tasks page by page, letting callers stop early.
+func (c *Client) IterTasks(ctx context.Context, params ListTasksParams) iter.Seq2[Task, error] {
+	return paginate[Task](ctx, c, c.tasksURL(params))
+}
+
+func (c *Client) tasksURL(params ListTasksParams) string {
 	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "tasks"})
 
 	query := endpoint.Query()
@@ -214,24 +368,28 @@ func (c *Client) ListTasks(ctx context.Context, params ListTasksParams) ([]Task,
 
 	endpoint.RawQuery = query.Encode()
 
-	var tasks []Task
-	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &tasks)
-	if err != nil {
-		return nil, err
-	}
-
-	return tasks, nil
+	return endpoint.String()
 }
 
 // ListUserStoriesParams defines filters for ListUserStories.
 type ListUserStoriesParams struct {
 	AssignedTo *int64
 	StatusID   *int64
+	EpicID     *int64
 	ProjectID  int64
 }
 
-// ListUserStories fetches user stories using optional filters.
+// ListUserStories fetches user stories using optional filters, following every result page.
 func (c *Client) ListUserStories(ctx context.Context, params ListUserStoriesParams) ([]UserStory, error) {
+	return listAll[UserStory](ctx, c, c.userStoriesURL(params))
+}
+
+// IterUserStories iterates over user stories page by page, letting callers stop early.
+func (c *Client) IterUserStories(ctx context.Context, params ListUserStoriesParams) iter.Seq2[UserStory, error] {
+	return paginate[UserStory](ctx, c, c.userStoriesURL(params))
+}
+
+func (c *Client) userStoriesURL(params ListUserStoriesParams) string {
 	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "userstories"})
 
 	query := endpoint.Query()
@@ -247,28 +405,40 @@ func (c *Client) ListUserStories(ctx context.Context, params ListUserStoriesPara
 		query.Set("status", strconv.FormatInt(*params.StatusID, 10))
 	}
 
-	endpoint.RawQuery = query.Encode()
-
-	var stories []UserStory
-	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &stories)
-	if err != nil {
-		return nil, err
+	if params.EpicID != nil {
+		query.Set("epic", strconv.FormatInt(*params.EpicID, 10))
 	}
 
-	return stories, nil
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
 }
 
-// ListProjects fetches projects available for current user.
+// ListProjects fetches projects available for current user, following every result page.
 func (c *Client) ListProjects(ctx context.Context) ([]Project, error) {
-	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "projects"})
+	return listAll[Project](ctx, c, c.baseURL.ResolveReference(&url.URL{Path: "projects"}).String())
+}
 
-	var projects []Project
-	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &projects)
-	if err != nil {
-		return nil, err
+// IterProjects iterates over projects available for current user page by page.
+func (c *Client) IterProjects(ctx context.Context) iter.Seq2[Project, error] {
+	return paginate[Project](ctx, c, c.baseURL.ResolveReference(&url.URL{Path: "projects"}).String())
+}
+
+// ListMemberProjects fetches the projects a user is a member of, following every result page.
+// Unlike ListProjects it leaves out public projects the user merely can see.
+func (c *Client) ListMemberProjects(ctx context.Context, userID int64) ([]Project, error) {
+	if userID <= 0 {
+		return nil, errors.New("некоректний id користувача")
 	}
 
-	return projects, nil
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "projects"})
+
+	query := endpoint.Query()
+	query.Set("member", strconv.FormatInt(userID, 10))
+
+	endpoint.RawQuery = query.Encode()
+
+	return listAll[Project](ctx, c, endpoint.String())
 }
 
 func (c *Client) ListMemberships(ctx context.Context, projectID int64) ([]Membership, error) {
@@ -276,95 +446,159 @@ func (c *Client) ListMemberships(ctx context.Context, projectID int64) ([]Member
 		return nil, errors.New("некоректний id проєкту")
 	}
 
+	return listAll[Membership](ctx, c, c.membershipsURL(projectID))
+}
+
+// IterMemberships iterates over project memberships page by page.
+func (c *Client) IterMemberships(ctx context.Context, projectID int64) iter.Seq2[Membership, error] {
+	if projectID <= 0 {
+		return func(yield func(Membership, error) bool) {
+			yield(Membership{}, errors.New("некоректний id проєкту"))
+		}
+	}
+
+	return paginate[Membership](ctx, c, c.membershipsURL(projectID))
+}
+
+func (c *Client) membershipsURL(projectID int64) string {
 	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "memberships"})
 	query := endpoint.Query()
 	query.Set("project", strconv.FormatInt(projectID, 10))
 
 	endpoint.RawQuery = query.Encode()
 
-	var memberships []Membership
-	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &memberships)
-	if err != nil {
-		return nil, err
-	}
-
-	return memberships, nil
+	return endpoint.String()
 }
 
 // do executes HTTP request and decodes the response.
 func (c *Client) do(ctx context.Context, method, endpoint string, payload, out any) error {
-	return c.doWithRetry(ctx, method, endpoint, payload, out, false)
+	_, err := c.doWithRetry(ctx, method, endpoint, payload, out, false)
+
+	return err
 }
 
-func (c *Client) doWithRetry(ctx context.Context, method, endpoint string, payload, out any, refreshed bool) error {
-	var body io.Reader
+// doWithRetry performs a request, refreshing the auth token once on 401 and
+// retrying transient failures according to the client's retry policy.
+func (c *Client) doWithRetry(ctx context.Context, method, endpoint string, payload, out any, refreshed bool) (http.Header, error) {
+	var body []byte
+
+	contentType := "application/json"
 	if payload != nil {
-		buf, err := json.Marshal(payload)
+		buf, payloadType, err := encodePayload(payload)
 		if err != nil {
-			return fmt.Errorf("не вдалося серіалізувати запит: %w", err)
+			return nil, fmt.Errorf("не вдалося серіалізувати запит: %w", err)
 		}
 
-		body = bytes.NewBuffer(buf)
+		body = buf
+		contentType = payloadType
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
-	if err != nil {
-		return fmt.Errorf("не вдалося сформувати запит: %w", err)
-	}
+	for attempt := 1; ; attempt++ {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
 
-	req.Header.Set("Content-Type", "application/json")
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+		if err != nil {
+			return nil, fmt.Errorf("не вдалося сформувати запит: %w", err)
+		}
 
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
-	}
+		req.Header.Set("Content-Type", contentType)
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("не вдалося виконати запит: %w", err)
-	}
-	defer resp.Body.Close()
-
-	bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+		authToken, err := c.tokens.validToken(ctx, c.exchangeRefreshToken)
+		if err != nil {
+			return nil, err
+		}
 
-	finalURL := endpoint
-	if resp.Request != nil && resp.Request.URL != nil {
-		finalURL = resp.Request.URL.String()
-	}
+		if authToken != "" {
+			req.Header.Set("Authorization", c.tokens.scheme+" "+authToken)
+		}
 
-	if resp.StatusCode == http.StatusUnauthorized && !refreshed && strings.TrimSpace(c.refresh) != "" {
-		err := c.refreshAuth(ctx)
+		resp, err := c.httpClient.Do(req)
 		if err != nil {
-			return err
+			err = fmt.Errorf("не вдалося виконати запит: %w", err)
+
+			wait, retry := c.retry.retryDelay(method, attempt, 0, nil, time.Now())
+			if !retry || ctx.Err() != nil {
+				return nil, err
+			}
+
+			if sleepErr := sleepContext(ctx, wait); sleepErr != nil {
+				return nil, errors.Join(err, sleepErr)
+			}
+
+			continue
 		}
-		return c.doWithRetry(ctx, method, endpoint, payload, out, true)
-	}
 
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("помилка API Taiga (%d) з %s: %s", resp.StatusCode, finalURL, truncateForLog(string(bodyBytes), 1024))
-	}
+		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 2*1024*1024))
+		resp.Body.Close()
+
+		finalURL := endpoint
+		if resp.Request != nil && resp.Request.URL != nil {
+			finalURL = resp.Request.URL.String()
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && !refreshed && c.tokens.canRefresh() {
+			err := c.tokens.refresh(ctx, authToken, c.exchangeRefreshToken)
+			if err != nil {
+				return nil, err
+			}
+
+			refreshed = true
+			attempt--
+
+			continue
+		}
+
+		if resp.StatusCode >= 300 {
+			respErr := newAPIError(resp.StatusCode, finalURL, bodyBytes)
+
+			wait, retry := c.retry.retryDelay(method, attempt, resp.StatusCode, resp.Header, time.Now())
+			if !retry {
+				return nil, respErr
+			}
+
+			if err := sleepContext(ctx, wait); err != nil {
+				return nil, errors.Join(respErr, err)
+			}
+
+			continue
+		}
+
+		if out == nil {
+			return resp.Header, nil
+		}
+
+		respType := resp.Header.Get("Content-Type")
+		if respType != "" && !strings.Contains(respType, "json") {
+			return nil, fmt.Errorf("API Taiga повернув не-JSON content-type %q з %s: %s", respType, finalURL, truncateForLog(string(bodyBytes), 1024))
+		}
 
-	if out == nil {
-		return nil
+		if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(out); err != nil {
+			return nil, fmt.Errorf("не вдалося розібрати відповідь з %s (content-type %q): %w", finalURL, respType, err)
+		}
+
+		return resp.Header, nil
 	}
+}
 
-	contentType := resp.Header.Get("Content-Type")
-	if contentType != "" && !strings.Contains(contentType, "json") {
-		return fmt.Errorf("API Taiga повернув не-JSON content-type %q з %s: %s", contentType, finalURL, truncateForLog(string(bodyBytes), 1024))
+// encodePayload serializes a request body, returning it with its content type.
+func encodePayload(payload any) ([]byte, string, error) {
+	if mp, ok := payload.(*multipartPayload); ok {
+		return mp.encode()
 	}
 
-	if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(out); err != nil {
-		return fmt.Errorf("не вдалося розібрати відповідь з %s (content-type %q): %w", finalURL, contentType, err)
+	buf, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", err
 	}
 
-	return nil
+	return buf, "application/json", nil
 }
 
-func (c *Client) refreshAuth(ctx context.Context) error {
-	refresh := strings.TrimSpace(c.refresh)
-	if refresh == "" {
-		return fmt.Errorf("потрібен refresh токен")
-	}
-
+// exchangeRefreshToken obtains new tokens from Taiga; callers go through TokenSource.refresh.
+func (c *Client) exchangeRefreshToken(ctx context.Context, refresh string) (string, string, error) {
 	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "auth/refresh"})
 	payload := struct {
 		Refresh string `json:"refresh"`
@@ -374,18 +608,18 @@ func (c *Client) refreshAuth(ctx context.Context) error {
 
 	buf, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("не вдалося серіалізувати refresh-запит: %w", err)
+		return "", "", fmt.Errorf("не вдалося серіалізувати refresh-запит: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.String(), bytes.NewReader(buf))
 	if err != nil {
-		return fmt.Errorf("не вдалося сформувати refresh-запит: %w", err)
+		return "", "", fmt.Errorf("не вдалося сформувати refresh-запит: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("не вдалося виконати refresh-запит: %w", err)
+		return "", "", fmt.Errorf("не вдалося виконати refresh-запит: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -396,7 +630,14 @@ func (c *Client) refreshAuth(ctx context.Context) error {
 	}
 
 	if resp.StatusCode >= 300 {
-		return fmt.Errorf("помилка refresh API Taiga (%d) з %s: %s", resp.StatusCode, finalURL, truncateForLog(string(bodyBytes), 1024))
+		apiErr := newAPIError(resp.StatusCode, finalURL, bodyBytes)
+
+		switch resp.StatusCode {
+		case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+			return "", "", fmt.Errorf("не вдалося оновити токен: %w: %w", ErrRefreshRejected, apiErr)
+		default:
+			return "", "", fmt.Errorf("не вдалося оновити токен: %w", apiErr)
+		}
 	}
 
 	var out struct {
@@ -404,21 +645,13 @@ func (c *Client) refreshAuth(ctx context.Context) error {
 		Refresh   string `json:"refresh"`
 	}
 	if err := json.NewDecoder(bytes.NewReader(bodyBytes)).Decode(&out); err != nil {
-		return fmt.Errorf("не вдалося розібрати refresh-відповідь з %s: %w", finalURL, err)
+		return "", "", fmt.Errorf("не вдалося розібрати refresh-відповідь з %s: %w", finalURL, err)
 	}
 	if strings.TrimSpace(out.AuthToken) == "" {
-		return fmt.Errorf("refresh не повернув auth_token")
+		return "", "", fmt.Errorf("refresh не повернув auth_token")
 	}
 
-	c.authToken = out.AuthToken
-	if strings.TrimSpace(out.Refresh) != "" {
-		c.refresh = out.Refresh
-	}
-	if c.onRefresh != nil {
-		c.onRefresh(c.authToken, c.refresh)
-	}
-
-	return nil
+	return out.AuthToken, out.Refresh, nil
 }
 
 func truncateForLog(body string, max int) string {
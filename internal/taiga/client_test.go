@@ -22,6 +22,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -208,3 +210,342 @@ func TestClient_AutoRefreshOnUnauthorized(t *testing.T) {
 		t.Fatalf("unexpected callback refresh token")
 	}
 }
+
+func TestClient_ListUserStoriesPaginated(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string][]UserStory{
+		"":  {{ID: 1, Ref: 1, Subject: "one"}, {ID: 2, Ref: 2, Subject: "two"}},
+		"2": {{ID: 3, Ref: 3, Subject: "three"}, {ID: 4, Ref: 4, Subject: "four"}},
+		"3": {{ID: 5, Ref: 5, Subject: "five"}},
+	}
+
+	var requests int
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.URL.Path != "/api/v1/userstories" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if got := r.URL.Query().Get("project"); got != "7" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("unexpected project: " + got))
+			return
+		}
+
+		page := r.URL.Query().Get("page")
+
+		items, ok := pages[page]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		next := map[string]string{"": "2", "2": "3"}[page]
+		if next != "" {
+			w.Header().Set("X-Pagination-Next", srv.URL+"/api/v1/userstories?project=7&page="+next)
+		}
+
+		w.Header().Set("X-Paginated", "true")
+		w.Header().Set("X-Paginated-Count", "5")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	got, err := c.ListUserStories(t.Context(), ListUserStoriesParams{ProjectID: 7})
+	if err != nil {
+		t.Fatalf("ListUserStories: %v", err)
+	}
+
+	if len(got) != 5 {
+		t.Fatalf("unexpected len: got=%d want=%d", len(got), 5)
+	}
+
+	for i, us := range got {
+		if us.ID != int64(i+1) {
+			t.Fatalf("unexpected item[%d]: %+v", i, us)
+		}
+	}
+
+	if requests != 3 {
+		t.Fatalf("unexpected requests: got=%d want=%d", requests, 3)
+	}
+}
+
+func TestClient_IterUserStoriesStopsEarly(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+
+		w.Header().Set("X-Pagination-Next", fmt.Sprintf("%s/api/v1/userstories?page=%d", srv.URL, page+1))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]UserStory{{ID: int64(page*2 - 1)}, {ID: int64(page * 2)}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var ids []int64
+
+	for us, err := range c.IterUserStories(t.Context(), ListUserStoriesParams{}) {
+		if err != nil {
+			t.Fatalf("IterUserStories: %v", err)
+		}
+
+		ids = append(ids, us.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+
+	if len(ids) != 3 || ids[2] != 3 {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+
+	if requests != 2 {
+		t.Fatalf("unexpected requests: got=%d want=%d", requests, 2)
+	}
+}
+
+func TestClient_IterProjectsYieldsError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	var gotErr error
+
+	for _, err := range c.IterProjects(t.Context()) {
+		gotErr = err
+	}
+
+	if gotErr == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestClient_ListUserStoriesRejectsForeignNextPage(t *testing.T) {
+	t.Parallel()
+
+	var leaked bool
+
+	foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = r.Header.Get("Authorization") != ""
+		_ = json.NewEncoder(w).Encode([]UserStory{})
+	}))
+	defer foreign.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Pagination-Next", foreign.URL+"/api/v1/userstories?page=2")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]UserStory{{ID: 1}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if _, err := c.ListUserStories(t.Context(), ListUserStoriesParams{}); err == nil {
+		t.Fatalf("expected error for a next page on another host")
+	}
+
+	for _, err := range c.IterUserStories(t.Context(), ListUserStoriesParams{}) {
+		if err != nil {
+			break
+		}
+	}
+
+	if leaked {
+		t.Fatalf("auth token sent to another host")
+	}
+}
+
+func TestClient_ListUserStoriesFailsPastPaginationCap(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		w.Header().Set("X-Pagination-Next", fmt.Sprintf("%s/api/v1/userstories?page=%d", srv.URL, page+1))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]UserStory{{ID: int64(page)}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if got, err := c.ListUserStories(t.Context(), ListUserStoriesParams{}); err == nil {
+		t.Fatalf("expected error instead of %d truncated items", len(got))
+	}
+}
+
+func TestClient_ListUserStoriesFailsOnPaginationLoop(t *testing.T) {
+	t.Parallel()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+
+		// The second page points back at the first one.
+		w.Header().Set("X-Pagination-Next", fmt.Sprintf("%s/api/v1/userstories?page=%d", srv.URL, (page+1)%2))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]UserStory{{ID: int64(page + 1)}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if got, err := c.ListUserStories(t.Context(), ListUserStoriesParams{}); err == nil {
+		t.Fatalf("expected error instead of %d items", len(got))
+	}
+
+	var gotErr error
+
+	for _, err := range c.IterUserStories(t.Context(), ListUserStoriesParams{}) {
+		if err != nil {
+			gotErr = err
+		}
+	}
+
+	if gotErr == nil {
+		t.Fatalf("expected IterUserStories to yield an error")
+	}
+}
+
+func TestClient_ListMemberProjects(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/projects" || r.URL.Query().Get("member") != "5" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]Project{{ID: 3, Slug: "team", Name: "Team"}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	got, err := c.ListMemberProjects(t.Context(), 5)
+	if err != nil {
+		t.Fatalf("ListMemberProjects: %v", err)
+	}
+
+	if len(got) != 1 || got[0].Slug != "team" {
+		t.Fatalf("unexpected projects: %+v", got)
+	}
+}
+
+func TestTags_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]Tags{
+		`[]`:                                  {},
+		`["backend", "urgent"]`:               {"backend", "urgent"},
+		`[["backend", "#fff"], ["ui", null]]`: {"backend", "ui"},
+		`[["backend", null], "ui"]`:           {"backend", "ui"},
+	}
+
+	for raw, want := range cases {
+		var tags Tags
+		if err := json.Unmarshal([]byte(raw), &tags); err != nil {
+			t.Fatalf("Unmarshal %s: %v", raw, err)
+		}
+
+		if !slices.Equal(tags, want) {
+			t.Fatalf("unexpected tags for %s: got=%v want=%v", raw, tags, want)
+		}
+	}
+}
+
+func TestClient_GetUserStory(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/userstories/5" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{
+			"id": 5, "ref": 12, "project": 3, "subject": "Login",
+			"description": "Allow users to sign in",
+			"tags": [["auth", "#f00"]],
+			"assigned_users": [7, 8], "assigned_to": 7, "owner": 9, "watchers": [9],
+			"milestone": 4, "milestone_name": "Sprint 1", "due_date": "2026-10-20",
+			"total_points": 5.5,
+			"project_extra_info": {"id": 3, "slug": "team-board", "name": "Team"}
+		}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	us, err := c.GetUserStory(t.Context(), 5)
+	if err != nil {
+		t.Fatalf("GetUserStory: %v", err)
+	}
+
+	if us.Description != "Allow users to sign in" || !slices.Equal(us.Tags, Tags{"auth"}) {
+		t.Fatalf("unexpected description or tags: %+v", us)
+	}
+
+	if !slices.Equal(us.AssignedUsers, []int64{7, 8}) || us.Owner == nil || *us.Owner != 9 {
+		t.Fatalf("unexpected people: %+v", us)
+	}
+
+	if us.MilestoneName != "Sprint 1" || us.DueDate != "2026-10-20" || us.TotalPoints != 5.5 {
+		t.Fatalf("unexpected planning fields: %+v", us)
+	}
+
+	if us.ProjectExtraInfo.Slug != "team-board" {
+		t.Fatalf("unexpected project info: %+v", us.ProjectExtraInfo)
+	}
+}
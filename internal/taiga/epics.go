@@ -0,0 +1,180 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// EpicCreateRequest represents payload accepted by Taiga for epic creation.
+type EpicCreateRequest struct {
+	StatusID    *int64   `json:"status,omitempty"`
+	Assigned    *int64   `json:"assigned_to,omitempty"`
+	Subject     string   `json:"subject"`
+	Description string   `json:"description,omitempty"`
+	Color       string   `json:"color,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	ProjectID   int64    `json:"project"`
+}
+
+// Epic represents a Taiga epic subset used by the bot.
+type Epic struct {
+	AssignedTo      *int64          `json:"assigned_to"`
+	Subject         string          `json:"subject"`
+	Color           string          `json:"color"`
+	StatusExtraInfo StatusExtraInfo `json:"status_extra_info"`
+	ID              int64           `json:"id"`
+	Ref             int64           `json:"ref"`
+	Project         int64           `json:"project"`
+	Version         int64           `json:"version"`
+	IsClosed        bool            `json:"is_closed"`
+}
+
+// EpicRelatedUserStory links a user story to an epic.
+type EpicRelatedUserStory struct {
+	EpicID      int64 `json:"epic"`
+	UserStoryID int64 `json:"user_story"`
+	Order       int64 `json:"order"`
+}
+
+// EpicProgress counts user stories related to an epic.
+type EpicProgress struct {
+	Closed int
+	Total  int
+}
+
+// ListEpicsParams defines filters for ListEpics.
+type ListEpicsParams struct {
+	AssignedTo *int64
+	StatusID   *int64
+	ProjectID  int64
+}
+
+// CreateEpic creates a new epic in Taiga.
+func (c *Client) CreateEpic(ctx context.Context, req EpicCreateRequest) (Epic, error) {
+	var epic Epic
+	if req.ProjectID == 0 || req.Subject == "" {
+		return epic, errors.New("потрібні проєкт і тема")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "epics"})
+	err := c.do(ctx, http.MethodPost, endpoint.String(), req, &epic)
+	if err != nil {
+		return epic, err
+	}
+
+	return epic, nil
+}
+
+// ListEpics fetches epics using optional filters, following every result page.
+func (c *Client) ListEpics(ctx context.Context, params ListEpicsParams) ([]Epic, error) {
+	return listAll[Epic](ctx, c, c.epicsURL(params))
+}
+
+// IterEpics iterates over epics page by page, letting callers stop early.
+func (c *Client) IterEpics(ctx context.Context, params ListEpicsParams) iter.Seq2[Epic, error] {
+	return paginate[Epic](ctx, c, c.epicsURL(params))
+}
+
+func (c *Client) epicsURL(params ListEpicsParams) string {
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "epics"})
+
+	query := endpoint.Query()
+	if params.ProjectID != 0 {
+		query.Set("project", strconv.FormatInt(params.ProjectID, 10))
+	}
+
+	if params.AssignedTo != nil {
+		query.Set("assigned_to", strconv.FormatInt(*params.AssignedTo, 10))
+	}
+
+	if params.StatusID != nil {
+		query.Set("status", strconv.FormatInt(*params.StatusID, 10))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
+
+// LinkUserStoryToEpic relates an existing user story to an epic.
+func (c *Client) LinkUserStoryToEpic(ctx context.Context, epicID, userStoryID int64) (EpicRelatedUserStory, error) {
+	var related EpicRelatedUserStory
+	if epicID <= 0 {
+		return related, errors.New("некоректний id епіка")
+	}
+
+	if userStoryID <= 0 {
+		return related, errors.New("некоректний id завдання")
+	}
+
+	payload := EpicRelatedUserStory{EpicID: epicID, UserStoryID: userStoryID}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("epics/%d/related_userstories", epicID)})
+	err := c.do(ctx, http.MethodPost, endpoint.String(), payload, &related)
+	if err != nil {
+		return related, err
+	}
+
+	return related, nil
+}
+
+// ListEpicRelatedUserStories fetches links between an epic and its user stories.
+func (c *Client) ListEpicRelatedUserStories(ctx context.Context, epicID int64) ([]EpicRelatedUserStory, error) {
+	if epicID <= 0 {
+		return nil, errors.New("некоректний id епіка")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("epics/%d/related_userstories", epicID)})
+
+	return listAll[EpicRelatedUserStory](ctx, c, endpoint.String())
+}
+
+// ListEpicProgress counts closed and total user stories of every epic in a project,
+// keyed by epic id. The project's user stories are read once and grouped by the
+// epics embedded in them, instead of one list request per epic.
+func (c *Client) ListEpicProgress(ctx context.Context, projectID int64) (map[int64]EpicProgress, error) {
+	if projectID <= 0 {
+		return nil, errors.New("некоректний id проєкту")
+	}
+
+	progress := make(map[int64]EpicProgress)
+
+	for us, err := range c.IterUserStories(ctx, ListUserStoriesParams{ProjectID: projectID}) {
+		if err != nil {
+			return nil, err
+		}
+
+		for _, epic := range us.Epics {
+			counts := progress[epic.ID]
+
+			counts.Total++
+			if us.IsClosed {
+				counts.Closed++
+			}
+
+			progress[epic.ID] = counts
+		}
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,124 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_LinkUserStoryToEpic(t *testing.T) {
+	t.Parallel()
+
+	errCh := make(chan error, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/epics/3/related_userstories" {
+			errCh <- fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var req EpicRelatedUserStory
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errCh <- fmt.Errorf("decode body: %w", err)
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if req.EpicID != 3 || req.UserStoryID != 9 {
+			errCh <- fmt.Errorf("unexpected payload: %+v", req)
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(EpicRelatedUserStory{EpicID: 3, UserStoryID: 9, Order: 100})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	got, err := c.LinkUserStoryToEpic(t.Context(), 3, 9)
+	if err != nil {
+		t.Fatalf("LinkUserStoryToEpic: %v", err)
+	}
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("server assertion failed: %v", err)
+	default:
+	}
+
+	if got.Order != 100 {
+		t.Fatalf("unexpected relation: %+v", got)
+	}
+}
+
+func TestClient_ListEpicProgress(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.URL.Path != "/api/v1/userstories" || r.URL.Query().Get("project") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("unexpected request: " + r.URL.String()))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]UserStory{
+			{ID: 1, IsClosed: true, Epics: []UserStoryEpic{{ID: 3}}},
+			{ID: 2, Epics: []UserStoryEpic{{ID: 3}, {ID: 4}}},
+			{ID: 3, IsClosed: true, Epics: []UserStoryEpic{{ID: 3}}},
+			{ID: 4},
+		})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	got, err := c.ListEpicProgress(t.Context(), 1)
+	if err != nil {
+		t.Fatalf("ListEpicProgress: %v", err)
+	}
+
+	if got[3] != (EpicProgress{Closed: 2, Total: 3}) || got[4] != (EpicProgress{Total: 1}) || len(got) != 2 {
+		t.Fatalf("unexpected progress: %+v", got)
+	}
+
+	if requests != 1 {
+		t.Fatalf("unexpected requests: got=%d want=%d", requests, 1)
+	}
+}
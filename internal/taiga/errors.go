@@ -0,0 +1,141 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Sentinel errors matched by errors.Is against an *APIError.
+var (
+	ErrUnauthorized = errors.New("Taiga не прийняла токен")
+	ErrForbidden    = errors.New("недостатньо прав у Taiga")
+	ErrNotFound     = errors.New("не знайдено в Taiga")
+	ErrRateLimited  = errors.New("забагато запитів до Taiga")
+	ErrValidation   = errors.New("Taiga відхилила дані")
+)
+
+// APIError is returned for non-2xx Taiga responses.
+type APIError struct {
+	// FieldErrors maps field names to the messages Taiga reported for them.
+	FieldErrors map[string][]string
+	URL         string
+	// Message is Taiga's "_error_message", if any.
+	Message    string
+	Body       []byte
+	StatusCode int
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("помилка API Taiga (%d) з %s: %s", e.StatusCode, e.URL, e.Reason())
+}
+
+// Reason returns Taiga's explanation: the error message, the field errors or the raw body.
+func (e *APIError) Reason() string {
+	switch {
+	case e.Message != "":
+		return e.Message
+	case len(e.FieldErrors) > 0:
+		return e.fieldSummary()
+	default:
+		return truncateForLog(string(e.Body), 1024)
+	}
+}
+
+// Is maps the status code onto the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest
+	default:
+		return false
+	}
+}
+
+func (e *APIError) fieldSummary() string {
+	fields := make([]string, 0, len(e.FieldErrors))
+	for field := range e.FieldErrors {
+		fields = append(fields, field)
+	}
+
+	slices.Sort(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+": "+strings.Join(e.FieldErrors[field], "; "))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// newAPIError builds an APIError, picking the message and field errors out of a JSON body.
+func newAPIError(statusCode int, url string, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, URL: url, Body: body}
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(body, &fields) != nil {
+		return apiErr
+	}
+
+	// Taiga's own errors use "_error_message"; authentication failures come with a "detail".
+	for _, key := range []string{"_error_message", "detail"} {
+		if raw, ok := fields[key]; ok && apiErr.Message == "" {
+			_ = json.Unmarshal(raw, &apiErr.Message)
+		}
+	}
+
+	for name, raw := range fields {
+		if strings.HasPrefix(name, "_") || name == "detail" || name == "code" {
+			continue
+		}
+
+		if apiErr.FieldErrors == nil {
+			apiErr.FieldErrors = make(map[string][]string)
+		}
+
+		apiErr.FieldErrors[name] = fieldMessages(raw)
+	}
+
+	return apiErr
+}
+
+// fieldMessages decodes a field error given as a list of messages, a single message or any other JSON value.
+func fieldMessages(raw json.RawMessage) []string {
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return []string{single}
+	}
+
+	return []string{string(raw)}
+}
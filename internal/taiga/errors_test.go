@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAPIError_Sentinels(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		want   error
+		status int
+	}{
+		{status: http.StatusUnauthorized, want: ErrUnauthorized},
+		{status: http.StatusForbidden, want: ErrForbidden},
+		{status: http.StatusNotFound, want: ErrNotFound},
+		{status: http.StatusTooManyRequests, want: ErrRateLimited},
+		{status: http.StatusBadRequest, want: ErrValidation},
+	}
+
+	sentinels := []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrRateLimited, ErrValidation}
+
+	for _, tc := range cases {
+		err := error(newAPIError(tc.status, "https://example.com", nil))
+
+		for _, sentinel := range sentinels {
+			if got := errors.Is(err, sentinel); got != (sentinel == tc.want) {
+				t.Fatalf("status %d: errors.Is(%v) = %v", tc.status, sentinel, got)
+			}
+		}
+	}
+}
+
+func TestNewAPIError_Body(t *testing.T) {
+	t.Parallel()
+
+	apiErr := newAPIError(http.StatusBadRequest, "u", []byte(`{"_error_message": "", "_error_type": "x", "subject": ["This field is required."], "version": "stale"}`))
+	if !slices.Equal(apiErr.FieldErrors["subject"], []string{"This field is required."}) || !slices.Equal(apiErr.FieldErrors["version"], []string{"stale"}) {
+		t.Fatalf("unexpected field errors: %+v", apiErr.FieldErrors)
+	}
+
+	if _, ok := apiErr.FieldErrors["_error_type"]; ok {
+		t.Fatalf("meta keys must not be field errors")
+	}
+
+	if !strings.Contains(apiErr.Error(), "subject: This field is required.") {
+		t.Fatalf("unexpected message: %s", apiErr.Error())
+	}
+
+	apiErr = newAPIError(http.StatusForbidden, "u", []byte(`{"_error_message": "You do not have permission"}`))
+	if apiErr.Message != "You do not have permission" || len(apiErr.FieldErrors) != 0 {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+
+	apiErr = newAPIError(http.StatusUnauthorized, "u", []byte(`{"detail": "Token is invalid", "code": "token_not_valid"}`))
+	if apiErr.Message != "Token is invalid" || len(apiErr.FieldErrors) != 0 {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+
+	apiErr = newAPIError(http.StatusBadGateway, "u", []byte("<html>bad gateway</html>"))
+	if apiErr.Message != "" || !strings.Contains(apiErr.Error(), "bad gateway") {
+		t.Fatalf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestClient_ReturnsAPIError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"_error_message": "No UserStory matches the given query."}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	_, err = c.GetUserStory(t.Context(), 5)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.URL != srv.URL+"/api/v1/userstories/5" {
+		t.Fatalf("unexpected APIError: %+v", apiErr)
+	}
+}
+
+func TestClient_RevokedRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"detail": "Token is invalid or expired", "code": "token_not_valid"}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithTokens(srv.URL+"/api/v1", "expired", "revoked", nil)
+	if err != nil {
+		t.Fatalf("NewClientWithTokens: %v", err)
+	}
+
+	if _, err := c.GetMe(t.Context()); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// History entry types reported by Taiga.
+const (
+	HistoryTypeChange = 1
+	HistoryTypeCreate = 2
+	HistoryTypeDelete = 3
+)
+
+// HistoryUser is the author of a history entry.
+type HistoryUser struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	ID       int64  `json:"pk"`
+}
+
+// DisplayName returns the full name of the author, or the username if it is missing.
+func (u HistoryUser) DisplayName() string {
+	if u.Name != "" {
+		return u.Name
+	}
+
+	return u.Username
+}
+
+// HistoryEntry is one change of a user story, task, issue or epic.
+// ValuesDiff maps changed fields to their old and new values in readable form:
+// status and user names rather than ids.
+type HistoryEntry struct {
+	CreatedAt  time.Time                  `json:"created_at"`
+	ValuesDiff map[string]json.RawMessage `json:"values_diff"`
+	User       HistoryUser                `json:"user"`
+	ID         string                     `json:"id"`
+	Comment    string                     `json:"comment"`
+	// DeleteCommentDate is set once the comment of the entry was deleted.
+	DeleteCommentDate *time.Time `json:"delete_comment_date"`
+	Type              int        `json:"type"`
+	IsHidden          bool       `json:"is_hidden"`
+}
+
+// ListHistory fetches the change history of an item, oldest entry first.
+func (c *Client) ListHistory(ctx context.Context, kind ItemKind, id int64) ([]HistoryEntry, error) {
+	if _, err := kind.collection(); err != nil {
+		return nil, err
+	}
+
+	if id <= 0 {
+		return nil, errors.New("некоректний id обʼєкта")
+	}
+
+	var entries []HistoryEntry
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("history/%s/%d", kind, id)})
+	if err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &entries); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+
+	return entries, nil
+}
+
+// HistorySince returns the entries that follow the entry with id lastID.
+// When lastID is empty or no longer listed, only the newest entry is returned.
+func HistorySince(entries []HistoryEntry, lastID string) []HistoryEntry {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if lastID != "" {
+		if i := slices.IndexFunc(entries, func(e HistoryEntry) bool { return e.ID == lastID }); i >= 0 {
+			return entries[i+1:]
+		}
+	}
+
+	return entries[len(entries)-1:]
+}
+
+// HasComment reports whether the entry carries a comment that was not deleted.
+func (e HistoryEntry) HasComment() bool {
+	return strings.TrimSpace(e.Comment) != "" && e.DeleteCommentDate == nil
+}
+
+// Fields returns the names of the changed fields in a stable order.
+func (e HistoryEntry) Fields() []string {
+	fields := make([]string, 0, len(e.ValuesDiff))
+	for field := range e.ValuesDiff {
+		fields = append(fields, field)
+	}
+
+	slices.Sort(fields)
+
+	return fields
+}
+
+// ValueChange returns the old and new value of a field changed from one single value to another.
+// Missing values are returned as empty strings.
+func (e HistoryEntry) ValueChange(field string) (from, to string, ok bool) {
+	raw, ok := e.ValuesDiff[field]
+	if !ok {
+		return "", "", false
+	}
+
+	var pair []json.RawMessage
+	if err := json.Unmarshal(raw, &pair); err != nil || len(pair) != 2 {
+		return "", "", false
+	}
+
+	return historyValue(pair[0]), historyValue(pair[1]), true
+}
+
+// ListChange returns the values added to and removed from a list field such as tags.
+func (e HistoryEntry) ListChange(field string) (added, removed []string, ok bool) {
+	raw, ok := e.ValuesDiff[field]
+	if !ok {
+		return nil, nil, false
+	}
+
+	var pair [2][]string
+	if err := json.Unmarshal(raw, &pair); err != nil {
+		return nil, nil, false
+	}
+
+	for _, value := range pair[1] {
+		if !slices.Contains(pair[0], value) {
+			added = append(added, value)
+		}
+	}
+
+	for _, value := range pair[0] {
+		if !slices.Contains(pair[1], value) {
+			removed = append(removed, value)
+		}
+	}
+
+	return added, removed, true
+}
+
+// PointsChange returns the old and new points per role of a user story.
+func (e HistoryEntry) PointsChange() (map[string][2]string, bool) {
+	raw, ok := e.ValuesDiff["points"]
+	if !ok {
+		return nil, false
+	}
+
+	var roles map[string][]json.RawMessage
+	if err := json.Unmarshal(raw, &roles); err != nil {
+		return nil, false
+	}
+
+	changes := make(map[string][2]string, len(roles))
+	for role, pair := range roles {
+		if len(pair) != 2 {
+			continue
+		}
+
+		changes[role] = [2]string{historyValue(pair[0]), historyValue(pair[1])}
+	}
+
+	return changes, true
+}
+
+// historyValue renders a single history value: a string, a number or a list of strings.
+func historyValue(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return strings.Join(list, ", ")
+	}
+
+	if string(raw) == "null" {
+		return ""
+	}
+
+	return string(raw)
+}
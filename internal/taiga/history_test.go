@@ -0,0 +1,105 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+const historyFixture = `[
+	{"id": "b", "created_at": "2026-03-02T10:00:00Z", "type": 1, "user": {"pk": 7, "name": "Olena", "username": "olena"},
+	 "comment": "Ready for review",
+	 "values_diff": {"status": ["In progress", "Review"], "tags": [["api", "ui"], ["api", "backend"]], "points": {"UX": ["1", "2"]}, "assigned_to": [null, "Taras"]}},
+	{"id": "a", "created_at": "2026-03-01T10:00:00Z", "type": 2, "user": {"pk": 7, "name": "", "username": "olena"}, "values_diff": {}},
+	{"id": "c", "created_at": "2026-03-03T10:00:00Z", "type": 1, "user": {"pk": 8, "name": "Taras"},
+	 "comment": "removed", "delete_comment_date": "2026-03-03T11:00:00Z", "values_diff": {"subject": ["Login", "Login page"]}}
+]`
+
+func TestClient_ListHistory(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/history/userstory/120" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(historyFixture))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	entries, err := c.ListHistory(context.Background(), KindUserStory, 120)
+	if err != nil {
+		t.Fatalf("ListHistory: %v", err)
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+
+	if !slices.Equal(ids, []string{"a", "b", "c"}) {
+		t.Fatalf("unexpected order: %v", ids)
+	}
+
+	if got := entries[0].User.DisplayName(); got != "olena" {
+		t.Fatalf("unexpected display name: %q", got)
+	}
+
+	change := entries[1]
+	if !change.HasComment() || entries[2].HasComment() {
+		t.Fatalf("unexpected comment visibility")
+	}
+
+	if from, to, ok := change.ValueChange("status"); !ok || from != "In progress" || to != "Review" {
+		t.Fatalf("unexpected status change: %q %q %v", from, to, ok)
+	}
+
+	if from, to, ok := change.ValueChange("assigned_to"); !ok || from != "" || to != "Taras" {
+		t.Fatalf("unexpected assignee change: %q %q %v", from, to, ok)
+	}
+
+	added, removed, ok := change.ListChange("tags")
+	if !ok || !slices.Equal(added, []string{"backend"}) || !slices.Equal(removed, []string{"ui"}) {
+		t.Fatalf("unexpected tags change: %v %v %v", added, removed, ok)
+	}
+
+	if points, ok := change.PointsChange(); !ok || points["UX"] != [2]string{"1", "2"} {
+		t.Fatalf("unexpected points change: %v %v", points, ok)
+	}
+
+	if got := HistorySince(entries, "a"); len(got) != 2 || got[0].ID != "b" {
+		t.Fatalf("unexpected entries since a: %v", got)
+	}
+
+	if got := HistorySince(entries, "gone"); len(got) != 1 || got[0].ID != "c" {
+		t.Fatalf("unexpected entries for unknown id: %v", got)
+	}
+
+	if got := HistorySince(entries, "c"); len(got) != 0 {
+		t.Fatalf("expected no new entries, got %v", got)
+	}
+}
@@ -0,0 +1,147 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// IssueCreateRequest represents payload accepted by Taiga for issue creation.
+type IssueCreateRequest struct {
+	StatusID    *int64   `json:"status,omitempty"`
+	Assigned    *int64   `json:"assigned_to,omitempty"`
+	SeverityID  *int64   `json:"severity,omitempty"`
+	PriorityID  *int64   `json:"priority,omitempty"`
+	TypeID      *int64   `json:"type,omitempty"`
+	Subject     string   `json:"subject"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	ProjectID   int64    `json:"project"`
+}
+
+// Issue represents a Taiga issue subset used by the bot.
+// Description is only sent by the detail endpoints, not by lists.
+type Issue struct {
+	AssignedTo       *int64          `json:"assigned_to"`
+	Owner            *int64          `json:"owner"`
+	Milestone        *int64          `json:"milestone"`
+	Severity         *int64          `json:"severity"`
+	Priority         *int64          `json:"priority"`
+	Type             *int64          `json:"type"`
+	Subject          string          `json:"subject"`
+	Description      string          `json:"description"`
+	DueDate          string          `json:"due_date"`
+	StatusExtraInfo  StatusExtraInfo `json:"status_extra_info"`
+	ProjectExtraInfo Project         `json:"project_extra_info"`
+	Watchers         []int64         `json:"watchers"`
+	Tags             Tags            `json:"tags"`
+	ID               int64           `json:"id"`
+	Ref              int64           `json:"ref"`
+	Project          int64           `json:"project"`
+	Version          int64           `json:"version"`
+	IsClosed         bool            `json:"is_closed"`
+}
+
+// ListIssuesParams defines filters for ListIssues.
+type ListIssuesParams struct {
+	AssignedTo *int64
+	StatusID   *int64
+	SeverityID *int64
+	PriorityID *int64
+	TypeID     *int64
+	ProjectID  int64
+}
+
+// CreateIssue creates a new issue in Taiga.
+func (c *Client) CreateIssue(ctx context.Context, req IssueCreateRequest) (Issue, error) {
+	var issue Issue
+	if req.ProjectID == 0 || req.Subject == "" {
+		return issue, errors.New("потрібні проєкт і тема")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "issues"})
+	err := c.do(ctx, http.MethodPost, endpoint.String(), req, &issue)
+	if err != nil {
+		return issue, err
+	}
+
+	return issue, nil
+}
+
+// GetIssue fetches issue by id.
+func (c *Client) GetIssue(ctx context.Context, id int64) (Issue, error) {
+	var issue Issue
+	if id <= 0 {
+		return issue, errors.New("некоректний id issue")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("issues/%d", id)})
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &issue)
+	if err != nil {
+		return issue, err
+	}
+
+	return issue, nil
+}
+
+// ListIssues fetches issues using optional filters, following every result page.
+func (c *Client) ListIssues(ctx context.Context, params ListIssuesParams) ([]Issue, error) {
+	return listAll[Issue](ctx, c, c.issuesURL(params))
+}
+
+// IterIssues iterates over issues page by page, letting callers stop early.
+func (c *Client) IterIssues(ctx context.Context, params ListIssuesParams) iter.Seq2[Issue, error] {
+	return paginate[Issue](ctx, c, c.issuesURL(params))
+}
+
+func (c *Client) issuesURL(params ListIssuesParams) string {
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "issues"})
+
+	query := endpoint.Query()
+	if params.ProjectID != 0 {
+		query.Set("project", strconv.FormatInt(params.ProjectID, 10))
+	}
+
+	if params.AssignedTo != nil {
+		query.Set("assigned_to", strconv.FormatInt(*params.AssignedTo, 10))
+	}
+
+	if params.StatusID != nil {
+		query.Set("status", strconv.FormatInt(*params.StatusID, 10))
+	}
+
+	if params.SeverityID != nil {
+		query.Set("severity", strconv.FormatInt(*params.SeverityID, 10))
+	}
+
+	if params.PriorityID != nil {
+		query.Set("priority", strconv.FormatInt(*params.PriorityID, 10))
+	}
+
+	if params.TypeID != nil {
+		query.Set("type", strconv.FormatInt(*params.TypeID, 10))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_CreateIssue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing_subject", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewClient("https://example.com/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		_, err = c.CreateIssue(t.Context(), IssueCreateRequest{ProjectID: 1})
+		if err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 1)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost || r.URL.Path != "/api/v1/issues" {
+				errCh <- fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			var req map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				errCh <- fmt.Errorf("decode body: %w", err)
+
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			if req["subject"] != "Crash" || req["severity"] != float64(3) || req["priority"] != float64(2) || req["type"] != float64(1) {
+				errCh <- fmt.Errorf("unexpected payload: %v", req)
+
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"id":                10,
+				"ref":               42,
+				"subject":           "Crash",
+				"project":           1,
+				"severity":          3,
+				"priority":          2,
+				"type":              1,
+				"status_extra_info": map[string]any{"name": "New"},
+			})
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		severity, priority, issueType := int64(3), int64(2), int64(1)
+
+		got, err := c.CreateIssue(t.Context(), IssueCreateRequest{
+			ProjectID:  1,
+			Subject:    "Crash",
+			SeverityID: &severity,
+			PriorityID: &priority,
+			TypeID:     &issueType,
+		})
+		if err != nil {
+			t.Fatalf("CreateIssue: %v", err)
+		}
+
+		select {
+		case err := <-errCh:
+			t.Fatalf("server assertion failed: %v", err)
+		default:
+		}
+
+		if got.Ref != 42 || got.StatusExtraInfo.Name != "New" {
+			t.Fatalf("unexpected issue: %+v", got)
+		}
+
+		if got.Severity == nil || *got.Severity != 3 {
+			t.Fatalf("unexpected severity: %v", got.Severity)
+		}
+	})
+}
+
+func TestClient_ListIssues(t *testing.T) {
+	t.Parallel()
+
+	errCh := make(chan error, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/api/v1/issues" || query.Get("project") != "1" || query.Get("assigned_to") != "5" {
+			errCh <- fmt.Errorf("unexpected request: %s", r.URL.String())
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]Issue{{ID: 1, Ref: 3, Subject: "Bug"}})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	assigned := int64(5)
+
+	got, err := c.ListIssues(t.Context(), ListIssuesParams{ProjectID: 1, AssignedTo: &assigned})
+	if err != nil {
+		t.Fatalf("ListIssues: %v", err)
+	}
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("server assertion failed: %v", err)
+	default:
+	}
+
+	if len(got) != 1 || got[0].Ref != 3 {
+		t.Fatalf("unexpected issues: %+v", got)
+	}
+}
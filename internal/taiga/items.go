@@ -0,0 +1,143 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ItemKind identifies a Taiga work item type.
+type ItemKind string
+
+const (
+	KindUserStory ItemKind = "userstory"
+	KindTask      ItemKind = "task"
+	KindIssue     ItemKind = "issue"
+	KindEpic      ItemKind = "epic"
+)
+
+// ItemKinds lists work item kinds in lookup order.
+// Epics are left out: they are only reached through ResolveRef.
+var ItemKinds = []ItemKind{KindUserStory, KindTask, KindIssue}
+
+// collection returns the API path segment for the kind.
+func (k ItemKind) collection() (string, error) {
+	switch k {
+	case KindUserStory:
+		return "userstories", nil
+	case KindTask:
+		return "tasks", nil
+	case KindIssue:
+		return "issues", nil
+	case KindEpic:
+		return "epics", nil
+	default:
+		return "", fmt.Errorf("невідомий тип обʼєкта Taiga: %q", string(k))
+	}
+}
+
+// Item is the subset shared by user stories, tasks, issues and epics.
+type Item struct {
+	AssignedTo      *int64          `json:"assigned_to"`
+	Kind            ItemKind        `json:"-"`
+	Subject         string          `json:"subject"`
+	StatusExtraInfo StatusExtraInfo `json:"status_extra_info"`
+	ID              int64           `json:"id"`
+	Ref             int64           `json:"ref"`
+	Project         int64           `json:"project"`
+	Version         int64           `json:"version"`
+}
+
+// GetItem fetches a user story, task, issue or epic by id.
+func (c *Client) GetItem(ctx context.Context, kind ItemKind, id int64) (Item, error) {
+	var item Item
+
+	collection, err := kind.collection()
+	if err != nil {
+		return item, err
+	}
+
+	if id <= 0 {
+		return item, errors.New("некоректний id обʼєкта")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("%s/%d", collection, id)})
+	if err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &item); err != nil {
+		return item, err
+	}
+
+	item.Kind = kind
+
+	return item, nil
+}
+
+// GetItemByRef fetches a user story, task or issue by its project reference number.
+func (c *Client) GetItemByRef(ctx context.Context, kind ItemKind, projectID, ref int64) (Item, error) {
+	var item Item
+
+	collection, err := kind.collection()
+	if err != nil {
+		return item, err
+	}
+
+	if projectID <= 0 || ref <= 0 {
+		return item, errors.New("потрібні проєкт і номер")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: collection + "/by_ref"})
+
+	query := endpoint.Query()
+	query.Set("project", strconv.FormatInt(projectID, 10))
+	query.Set("ref", strconv.FormatInt(ref, 10))
+
+	endpoint.RawQuery = query.Encode()
+
+	if err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &item); err != nil {
+		return item, err
+	}
+
+	item.Kind = kind
+
+	return item, nil
+}
+
+// AddComment posts a comment to a user story, task or issue.
+// Taiga records comments through a versioned PATCH; a comment never clashes with
+// concurrent field edits, so a stale version is refreshed and the call retried.
+func (c *Client) AddComment(ctx context.Context, kind ItemKind, id int64, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("потрібен текст коментаря")
+	}
+
+	_, err := updateItem[Item](ctx, c, kind, id, 0, UpdateOptions{RetryOnConflict: true}, func(version int64) any {
+		return struct {
+			Comment string `json:"comment"`
+			Version int64  `json:"version"`
+		}{
+			Comment: text,
+			Version: version,
+		}
+	})
+
+	return err
+}
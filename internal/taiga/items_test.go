@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_AddComment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty_text", func(t *testing.T) {
+		t.Parallel()
+
+		c, err := NewClient("https://example.com/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		if err := c.AddComment(t.Context(), KindTask, 1, "  "); err == nil {
+			t.Fatalf("expected error")
+		}
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+
+		errCh := make(chan error, 1)
+
+		var patched bool
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/v1/issues/7" {
+				errCh <- fmt.Errorf("unexpected path: %s", r.URL.Path)
+
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			switch r.Method {
+			case http.MethodGet:
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": 7, "ref": 3, "version": 4})
+			case http.MethodPatch:
+				var req map[string]any
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					errCh <- fmt.Errorf("decode body: %w", err)
+
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+
+				if req["comment"] != "looks good" || req["version"] != float64(4) {
+					errCh <- fmt.Errorf("unexpected payload: %v", req)
+
+					w.WriteHeader(http.StatusBadRequest)
+
+					return
+				}
+
+				patched = true
+
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(map[string]any{"id": 7, "ref": 3, "version": 5})
+			default:
+				errCh <- fmt.Errorf("unexpected method: %s", r.Method)
+
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		if err := c.AddComment(t.Context(), KindIssue, 7, "looks good"); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+
+		select {
+		case err := <-errCh:
+			t.Fatalf("server assertion failed: %v", err)
+		default:
+		}
+
+		if !patched {
+			t.Fatalf("expected PATCH request")
+		}
+	})
+}
@@ -0,0 +1,257 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMetadataTTL is how long project metadata stays cached unless configured otherwise.
+const DefaultMetadataTTL = 10 * time.Minute
+
+// Status represents a user story, task or issue status of a project.
+type Status struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Color    string `json:"color"`
+	ID       int64  `json:"id"`
+	Project  int64  `json:"project"`
+	Order    int64  `json:"order"`
+	IsClosed bool   `json:"is_closed"`
+}
+
+// Attribute represents a project priority, severity or issue type.
+type Attribute struct {
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	ID      int64  `json:"id"`
+	Project int64  `json:"project"`
+	Order   int64  `json:"order"`
+}
+
+// Point represents an estimation value of a project.
+type Point struct {
+	Value   *float64 `json:"value"`
+	Name    string   `json:"name"`
+	ID      int64    `json:"id"`
+	Project int64    `json:"project"`
+	Order   int64    `json:"order"`
+}
+
+// MetadataCache keeps project metadata lists for a limited time.
+// One cache may be shared by many clients, but entries are kept per Taiga user:
+// what Taiga returns depends on the user's access to the project.
+type MetadataCache struct {
+	entries map[metadataKey]metadataEntry
+	now     func() time.Time
+	ttl     time.Duration
+	mu      sync.Mutex
+}
+
+type metadataKey struct {
+	resource  string
+	projectID int64
+	userID    int64
+}
+
+type metadataEntry struct {
+	expires time.Time
+	value   any
+}
+
+// NewMetadataCache returns a cache whose entries expire after ttl.
+func NewMetadataCache(ttl time.Duration) *MetadataCache {
+	if ttl <= 0 {
+		ttl = DefaultMetadataTTL
+	}
+
+	return &MetadataCache{
+		entries: make(map[metadataKey]metadataEntry),
+		now:     time.Now,
+		ttl:     ttl,
+	}
+}
+
+// Invalidate drops every cached list of a project, for every user.
+func (m *MetadataCache) Invalidate(projectID int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key := range m.entries {
+		if key.projectID == projectID {
+			delete(m.entries, key)
+		}
+	}
+}
+
+func (m *MetadataCache) get(key metadataKey) (any, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+func (m *MetadataCache) set(key metadataKey, value any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries[key] = metadataEntry{value: value, expires: m.now().Add(m.ttl)}
+}
+
+// SetMetadataCache makes the client share a metadata cache with the other clients
+// of the Taiga user userID.
+func (c *Client) SetMetadataCache(cache *MetadataCache, userID int64) {
+	if cache != nil {
+		c.metadata = cache
+		c.metadataUser = userID
+	}
+}
+
+// ListUserStoryStatuses fetches user story statuses of a project.
+func (c *Client) ListUserStoryStatuses(ctx context.Context, projectID int64) ([]Status, error) {
+	return cachedMetadata[Status](ctx, c, "userstory-statuses", projectID)
+}
+
+// ListTaskStatuses fetches task statuses of a project.
+func (c *Client) ListTaskStatuses(ctx context.Context, projectID int64) ([]Status, error) {
+	return cachedMetadata[Status](ctx, c, "task-statuses", projectID)
+}
+
+// ListIssueStatuses fetches issue statuses of a project.
+func (c *Client) ListIssueStatuses(ctx context.Context, projectID int64) ([]Status, error) {
+	return cachedMetadata[Status](ctx, c, "issue-statuses", projectID)
+}
+
+// ListPriorities fetches issue priorities of a project.
+func (c *Client) ListPriorities(ctx context.Context, projectID int64) ([]Attribute, error) {
+	return cachedMetadata[Attribute](ctx, c, "priorities", projectID)
+}
+
+// ListSeverities fetches issue severities of a project.
+func (c *Client) ListSeverities(ctx context.Context, projectID int64) ([]Attribute, error) {
+	return cachedMetadata[Attribute](ctx, c, "severities", projectID)
+}
+
+// ListIssueTypes fetches issue types of a project.
+func (c *Client) ListIssueTypes(ctx context.Context, projectID int64) ([]Attribute, error) {
+	return cachedMetadata[Attribute](ctx, c, "issue-types", projectID)
+}
+
+// ListPoints fetches estimation points of a project.
+func (c *Client) ListPoints(ctx context.Context, projectID int64) ([]Point, error) {
+	return cachedMetadata[Point](ctx, c, "points", projectID)
+}
+
+// ListEpicStatuses fetches epic statuses of a project.
+func (c *Client) ListEpicStatuses(ctx context.Context, projectID int64) ([]Status, error) {
+	return cachedMetadata[Status](ctx, c, "epic-statuses", projectID)
+}
+
+// ListStatuses fetches the statuses that apply to an item kind.
+func (c *Client) ListStatuses(ctx context.Context, kind ItemKind, projectID int64) ([]Status, error) {
+	switch kind {
+	case KindUserStory:
+		return c.ListUserStoryStatuses(ctx, projectID)
+	case KindTask:
+		return c.ListTaskStatuses(ctx, projectID)
+	case KindIssue:
+		return c.ListIssueStatuses(ctx, projectID)
+	case KindEpic:
+		return c.ListEpicStatuses(ctx, projectID)
+	default:
+		return nil, fmt.Errorf("невідомий тип обʼєкта Taiga: %q", string(kind))
+	}
+}
+
+// ResolveStatus finds a status of an item kind by its name or slug, ignoring case.
+func (c *Client) ResolveStatus(ctx context.Context, kind ItemKind, projectID int64, name string) (Status, error) {
+	statuses, err := c.ListStatuses(ctx, kind, projectID)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status, ok := FindStatus(statuses, name)
+	if !ok {
+		return Status{}, fmt.Errorf("невідомий статус %q", strings.TrimSpace(name))
+	}
+
+	return status, nil
+}
+
+// FindStatus looks a status up by name or slug, ignoring case and surrounding spaces.
+func FindStatus(statuses []Status, name string) (Status, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Status{}, false
+	}
+
+	for _, s := range statuses {
+		if strings.EqualFold(s.Name, name) || strings.EqualFold(s.Slug, name) {
+			return s, true
+		}
+	}
+
+	return Status{}, false
+}
+
+// cachedMetadata returns a project metadata list, serving it from the cache while fresh.
+func cachedMetadata[T any](ctx context.Context, c *Client, resource string, projectID int64) ([]T, error) {
+	if projectID <= 0 {
+		return nil, errors.New("некоректний id проєкту")
+	}
+
+	key := metadataKey{resource: resource, projectID: projectID, userID: c.metadataUser}
+
+	if cached, ok := c.metadata.get(key); ok {
+		if items, ok := cached.([]T); ok {
+			return slices.Clone(items), nil
+		}
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: resource})
+
+	query := endpoint.Query()
+	query.Set("project", strconv.FormatInt(projectID, 10))
+
+	endpoint.RawQuery = query.Encode()
+
+	items, err := listAll[T](ctx, c, endpoint.String())
+	if err != nil {
+		return nil, err
+	}
+
+	c.metadata.set(key, slices.Clone(items))
+
+	return items, nil
+}
@@ -0,0 +1,135 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_MetadataCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		calls = make(map[string]int)
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Path+"?"+r.URL.RawQuery]++
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/userstory-statuses":
+			_ = json.NewEncoder(w).Encode([]Status{
+				{ID: 1, Name: "New", Slug: "new"},
+				{ID: 2, Name: "In progress", Slug: "in-progress"},
+				{ID: 3, Name: "Done", Slug: "done", IsClosed: true},
+			})
+		case "/api/v1/severities":
+			_ = json.NewEncoder(w).Encode([]Attribute{{ID: 4, Name: "Critical"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	cache := NewMetadataCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	c1, err := NewClient(srv.URL+"/api/v1", "token-1")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	c2, err := NewClient(srv.URL+"/api/v1", "token-2")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	c1.SetMetadataCache(cache, 10)
+	c2.SetMetadataCache(cache, 10)
+
+	status, err := c1.ResolveStatus(t.Context(), KindUserStory, 1, "  in PROGRESS ")
+	if err != nil {
+		t.Fatalf("ResolveStatus: %v", err)
+	}
+
+	if status.ID != 2 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	if _, err := c2.ResolveStatus(t.Context(), KindUserStory, 1, "done"); err != nil {
+		t.Fatalf("ResolveStatus by slug: %v", err)
+	}
+
+	if _, err := c2.ResolveStatus(t.Context(), KindUserStory, 1, "Archived"); err == nil {
+		t.Fatalf("expected unknown status error")
+	}
+
+	if got := calls["/api/v1/userstory-statuses?project=1"]; got != 1 {
+		t.Fatalf("expected one request while cached, got %d", got)
+	}
+
+	// Another Taiga user does not get the lists fetched with someone else's token.
+	other, err := NewClient(srv.URL+"/api/v1", "token-3")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	other.SetMetadataCache(cache, 20)
+
+	if _, err := other.ListUserStoryStatuses(t.Context(), 1); err != nil {
+		t.Fatalf("ListUserStoryStatuses: %v", err)
+	}
+
+	if got := calls["/api/v1/userstory-statuses?project=1"]; got != 2 {
+		t.Fatalf("expected a separate request for another user, got %d", got)
+	}
+
+	if _, err := c1.ListSeverities(t.Context(), 1); err != nil {
+		t.Fatalf("ListSeverities: %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+
+	if _, err := c1.ListUserStoryStatuses(t.Context(), 1); err != nil {
+		t.Fatalf("ListUserStoryStatuses: %v", err)
+	}
+
+	if got := calls["/api/v1/userstory-statuses?project=1"]; got != 3 {
+		t.Fatalf("expected refetch after ttl, got %d", got)
+	}
+
+	cache.Invalidate(1)
+
+	if _, err := c1.ListSeverities(t.Context(), 1); err != nil {
+		t.Fatalf("ListSeverities: %v", err)
+	}
+
+	if got := calls["/api/v1/severities?project=1"]; got != 2 {
+		t.Fatalf("expected refetch after invalidate, got %d", got)
+	}
+}
@@ -0,0 +1,294 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const milestoneDateLayout = "2006-01-02"
+
+// Points holds a points total that Taiga reports either as a number,
+// a per-role map or a list of values depending on the endpoint.
+type Points float64
+
+// UnmarshalJSON sums numeric values found in the supported encodings.
+func (p *Points) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*p = 0
+		return nil
+	}
+
+	switch data[0] {
+	case '{':
+		var byRole map[string]*float64
+		if err := json.Unmarshal(data, &byRole); err != nil {
+			return err
+		}
+
+		var total float64
+		for _, v := range byRole {
+			if v != nil {
+				total += *v
+			}
+		}
+
+		*p = Points(total)
+
+	case '[':
+		var values []*float64
+		if err := json.Unmarshal(data, &values); err != nil {
+			return err
+		}
+
+		var total float64
+		for _, v := range values {
+			if v != nil {
+				total += *v
+			}
+		}
+
+		*p = Points(total)
+
+	default:
+		var v float64
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+
+		*p = Points(v)
+	}
+
+	return nil
+}
+
+// Milestone represents a Taiga milestone (sprint) subset used by the bot.
+type Milestone struct {
+	Name            string      `json:"name"`
+	Slug            string      `json:"slug"`
+	EstimatedStart  string      `json:"estimated_start"`
+	EstimatedFinish string      `json:"estimated_finish"`
+	UserStories     []UserStory `json:"user_stories"`
+	TotalPoints     Points      `json:"total_points"`
+	ClosedPoints    Points      `json:"closed_points"`
+	ID              int64       `json:"id"`
+	Project         int64       `json:"project"`
+	Closed          bool        `json:"closed"`
+}
+
+// Start parses EstimatedStart.
+func (m Milestone) Start() (time.Time, bool) {
+	t, err := time.Parse(milestoneDateLayout, m.EstimatedStart)
+	return t, err == nil
+}
+
+// Finish parses EstimatedFinish.
+func (m Milestone) Finish() (time.Time, bool) {
+	t, err := time.Parse(milestoneDateLayout, m.EstimatedFinish)
+	return t, err == nil
+}
+
+// MilestoneStatsDay is one burndown point of a milestone.
+type MilestoneStatsDay struct {
+	Day           string  `json:"day"`
+	OpenPoints    float64 `json:"open_points"`
+	OptimalPoints float64 `json:"optimal_points"`
+}
+
+// MilestoneStats represents burndown statistics for a milestone.
+type MilestoneStats struct {
+	Name                 string              `json:"name"`
+	EstimatedStart       string              `json:"estimated_start"`
+	EstimatedFinish      string              `json:"estimated_finish"`
+	Days                 []MilestoneStatsDay `json:"days"`
+	TotalPoints          Points              `json:"total_points"`
+	CompletedPoints      Points              `json:"completed_points"`
+	TotalUserStories     int                 `json:"total_userstories"`
+	CompletedUserStories int                 `json:"completed_userstories"`
+	TotalTasks           int                 `json:"total_tasks"`
+	CompletedTasks       int                 `json:"completed_tasks"`
+}
+
+// DayOn returns the latest burndown point on or before the given date.
+func (s MilestoneStats) DayOn(date time.Time) (MilestoneStatsDay, bool) {
+	var (
+		found MilestoneStatsDay
+		ok    bool
+	)
+
+	day := date.Format(milestoneDateLayout)
+	for _, d := range s.Days {
+		if d.Day > day {
+			continue
+		}
+
+		if !ok || d.Day > found.Day {
+			found = d
+			ok = true
+		}
+	}
+
+	return found, ok
+}
+
+// ListMilestonesParams defines filters for ListMilestones.
+type ListMilestonesParams struct {
+	Closed    *bool
+	ProjectID int64
+}
+
+// ListMilestones fetches milestones using optional filters, following every result page.
+func (c *Client) ListMilestones(ctx context.Context, params ListMilestonesParams) ([]Milestone, error) {
+	return listAll[Milestone](ctx, c, c.milestonesURL(params))
+}
+
+// IterMilestones iterates over milestones page by page, letting callers stop early.
+func (c *Client) IterMilestones(ctx context.Context, params ListMilestonesParams) iter.Seq2[Milestone, error] {
+	return paginate[Milestone](ctx, c, c.milestonesURL(params))
+}
+
+func (c *Client) milestonesURL(params ListMilestonesParams) string {
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "milestones"})
+
+	query := endpoint.Query()
+	if params.ProjectID != 0 {
+		query.Set("project", strconv.FormatInt(params.ProjectID, 10))
+	}
+
+	if params.Closed != nil {
+		query.Set("closed", strconv.FormatBool(*params.Closed))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	return endpoint.String()
+}
+
+// GetMilestone fetches milestone by id including its user stories.
+func (c *Client) GetMilestone(ctx context.Context, id int64) (Milestone, error) {
+	var milestone Milestone
+	if id <= 0 {
+		return milestone, errors.New("некоректний id спринту")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("milestones/%d", id)})
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &milestone)
+	if err != nil {
+		return milestone, err
+	}
+
+	return milestone, nil
+}
+
+// GetMilestoneStats fetches burndown statistics for a milestone.
+func (c *Client) GetMilestoneStats(ctx context.Context, id int64) (MilestoneStats, error) {
+	var stats MilestoneStats
+	if id <= 0 {
+		return stats, errors.New("некоректний id спринту")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("milestones/%d/stats", id)})
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &stats)
+	if err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// MoveUserStoriesToMilestone puts user stories into a milestone.
+func (c *Client) MoveUserStoriesToMilestone(ctx context.Context, projectID, milestoneID int64, userStoryIDs []int64) error {
+	if projectID <= 0 {
+		return errors.New("некоректний id проєкту")
+	}
+
+	if milestoneID <= 0 {
+		return errors.New("некоректний id спринту")
+	}
+
+	if len(userStoryIDs) == 0 {
+		return errors.New("потрібне хоча б одне завдання")
+	}
+
+	type bulkStory struct {
+		UserStoryID int64 `json:"us_id"`
+		Order       int   `json:"order"`
+	}
+
+	payload := struct {
+		BulkStories []bulkStory `json:"bulk_stories"`
+		ProjectID   int64       `json:"project_id"`
+		MilestoneID int64       `json:"milestone_id"`
+	}{
+		ProjectID:   projectID,
+		MilestoneID: milestoneID,
+	}
+
+	for i, id := range userStoryIDs {
+		payload.BulkStories = append(payload.BulkStories, bulkStory{UserStoryID: id, Order: i + 1})
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "userstories/bulk_update_milestone"})
+
+	return c.do(ctx, http.MethodPost, endpoint.String(), payload, nil)
+}
+
+// CurrentMilestone picks the open milestone whose dates contain now,
+// falling back to the nearest upcoming open milestone.
+func CurrentMilestone(milestones []Milestone, now time.Time) (Milestone, bool) {
+	today, _ := time.Parse(milestoneDateLayout, now.Format(milestoneDateLayout))
+
+	var (
+		upcoming    Milestone
+		upcomingAt  time.Time
+		hasUpcoming bool
+	)
+
+	for _, m := range milestones {
+		if m.Closed {
+			continue
+		}
+
+		start, okStart := m.Start()
+		finish, okFinish := m.Finish()
+
+		if !okStart || !okFinish {
+			continue
+		}
+
+		if !today.Before(start) && !today.After(finish) {
+			return m, true
+		}
+
+		if start.After(today) && (!hasUpcoming || start.Before(upcomingAt)) {
+			upcoming = m
+			upcomingAt = start
+			hasUpcoming = true
+		}
+	}
+
+	return upcoming, hasUpcoming
+}
@@ -0,0 +1,181 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPoints_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]float64{
+		`null`:                 0,
+		`12.5`:                 12.5,
+		`{"1": 3, "2": null}`:  3,
+		`[1, 2.5, null]`:       3.5,
+		`{"1": 1.5, "2": 2.5}`: 4,
+	}
+
+	for raw, want := range cases {
+		var p Points
+		if err := json.Unmarshal([]byte(raw), &p); err != nil {
+			t.Fatalf("Unmarshal %s: %v", raw, err)
+		}
+
+		if float64(p) != want {
+			t.Fatalf("unexpected points for %s: got=%v want=%v", raw, p, want)
+		}
+	}
+}
+
+func TestCurrentMilestone(t *testing.T) {
+	t.Parallel()
+
+	milestones := []Milestone{
+		{ID: 1, EstimatedStart: "2026-09-01", EstimatedFinish: "2026-09-14", Closed: true},
+		{ID: 2, EstimatedStart: "2026-10-20", EstimatedFinish: "2026-11-02"},
+		{ID: 3, EstimatedStart: "2026-10-06", EstimatedFinish: "2026-10-19"},
+		{ID: 4, EstimatedStart: "2026-11-03", EstimatedFinish: "2026-11-16"},
+	}
+
+	now := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+
+	got, ok := CurrentMilestone(milestones, now)
+	if !ok || got.ID != 3 {
+		t.Fatalf("unexpected current milestone: ok=%v got=%+v", ok, got)
+	}
+
+	got, ok = CurrentMilestone(milestones, now.AddDate(0, 0, 4))
+	if !ok || got.ID != 2 {
+		t.Fatalf("unexpected milestone after finish: ok=%v got=%+v", ok, got)
+	}
+
+	_, ok = CurrentMilestone(milestones, now.AddDate(1, 0, 0))
+	if ok {
+		t.Fatalf("expected no milestone")
+	}
+}
+
+func TestClient_GetMilestoneStats(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/milestones/5/stats" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"name": "Sprint 5",
+			"estimated_start": "2026-10-06",
+			"estimated_finish": "2026-10-19",
+			"total_points": {"1": 20, "2": 20},
+			"completed_points": [5, 8],
+			"total_userstories": 10,
+			"completed_userstories": 4,
+			"days": [
+				{"day": "2026-10-06", "open_points": 40, "optimal_points": 40},
+				{"day": "2026-10-15", "open_points": 30, "optimal_points": 14},
+				{"day": "2026-10-16", "open_points": 27, "optimal_points": 11}
+			]
+		}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	stats, err := c.GetMilestoneStats(t.Context(), 5)
+	if err != nil {
+		t.Fatalf("GetMilestoneStats: %v", err)
+	}
+
+	if stats.TotalPoints != 40 || stats.CompletedPoints != 13 || stats.CompletedUserStories != 4 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	day, ok := stats.DayOn(time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC))
+	if !ok || day.OpenPoints != 30 {
+		t.Fatalf("unexpected burndown day: ok=%v day=%+v", ok, day)
+	}
+}
+
+func TestClient_MoveUserStoriesToMilestone(t *testing.T) {
+	t.Parallel()
+
+	errCh := make(chan error, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/userstories/bulk_update_milestone" {
+			errCh <- fmt.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		var req struct {
+			BulkStories []struct {
+				UserStoryID int64 `json:"us_id"`
+				Order       int   `json:"order"`
+			} `json:"bulk_stories"`
+			ProjectID   int64 `json:"project_id"`
+			MilestoneID int64 `json:"milestone_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			errCh <- fmt.Errorf("decode body: %w", err)
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		if req.ProjectID != 1 || req.MilestoneID != 5 || len(req.BulkStories) != 2 || req.BulkStories[1].UserStoryID != 8 {
+			errCh <- fmt.Errorf("unexpected payload: %+v", req)
+
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	if err := c.MoveUserStoriesToMilestone(t.Context(), 1, 5, []int64{7, 8}); err != nil {
+		t.Fatalf("MoveUserStoriesToMilestone: %v", err)
+	}
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("server assertion failed: %v", err)
+	default:
+	}
+}
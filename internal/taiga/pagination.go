@@ -0,0 +1,192 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	paginationNextHeader  = "X-Pagination-Next"
+	paginatedCountHeader  = "X-Paginated-Count"
+	maxPaginationRequests = 1000
+)
+
+// pageInfo describes pagination headers returned by Taiga list endpoints.
+type pageInfo struct {
+	next  string
+	count int
+}
+
+func parsePageInfo(header http.Header) pageInfo {
+	var info pageInfo
+	if header == nil {
+		return info
+	}
+
+	info.next = strings.TrimSpace(header.Get(paginationNextHeader))
+
+	if raw := strings.TrimSpace(header.Get(paginatedCountHeader)); raw != "" {
+		if count, err := strconv.Atoi(raw); err == nil && count > 0 {
+			info.count = count
+		}
+	}
+
+	return info
+}
+
+// fetchPage requests a single page of a list endpoint.
+func fetchPage[T any](ctx context.Context, c *Client, endpoint string) ([]T, pageInfo, error) {
+	var items []T
+
+	header, err := c.doWithRetry(ctx, http.MethodGet, endpoint, nil, &items, false)
+	if err != nil {
+		return nil, pageInfo{}, err
+	}
+
+	return items, parsePageInfo(header), nil
+}
+
+// paginate walks every page of a list endpoint by following x-pagination-next.
+// Iteration stops at the first error, which is yielded with a zero item.
+func paginate[T any](ctx context.Context, c *Client, endpoint string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		seen := make(map[string]struct{})
+
+		next := endpoint
+		for requests := 0; next != ""; requests++ {
+			// Guard against servers that point x-pagination-next back at a page we already read.
+			if _, ok := seen[next]; ok {
+				yield(zero, errPaginationLoop(endpoint))
+
+				return
+			}
+
+			if requests >= maxPaginationRequests {
+				yield(zero, errTooManyPages(endpoint))
+
+				return
+			}
+
+			seen[next] = struct{}{}
+
+			items, info, err := fetchPage[T](ctx, c, next)
+			if err != nil {
+				yield(zero, err)
+
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			next, err = c.nextPageURL(info.next)
+			if err != nil {
+				yield(zero, err)
+
+				return
+			}
+		}
+	}
+}
+
+// listAll collects every page of a list endpoint into a single slice.
+func listAll[T any](ctx context.Context, c *Client, endpoint string) ([]T, error) {
+	items, info, err := fetchPage[T](ctx, c, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.next == "" {
+		return items, nil
+	}
+
+	result := make([]T, 0, max(info.count, len(items)))
+	result = append(result, items...)
+
+	seen := map[string]struct{}{endpoint: {}}
+
+	for requests := 1; ; requests++ {
+		next, err := c.nextPageURL(info.next)
+		if err != nil {
+			return nil, err
+		}
+
+		if next == "" {
+			break
+		}
+
+		if _, ok := seen[next]; ok {
+			return nil, errPaginationLoop(endpoint)
+		}
+
+		if requests >= maxPaginationRequests {
+			return nil, errTooManyPages(endpoint)
+		}
+
+		seen[next] = struct{}{}
+
+		items, info, err = fetchPage[T](ctx, c, next)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, items...)
+	}
+
+	return result, nil
+}
+
+// nextPageURL checks x-pagination-next before the auth token is sent to it:
+// the next page must live on the same scheme and host as the Taiga API.
+func (c *Client) nextPageURL(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+
+	next, err := c.baseURL.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("некоректне посилання на наступну сторінку Taiga: %w", err)
+	}
+
+	if next.Scheme != c.baseURL.Scheme || next.Host != c.baseURL.Host {
+		return "", fmt.Errorf("Taiga вказала наступну сторінку на іншому хості: %s://%s", next.Scheme, next.Host)
+	}
+
+	return next.String(), nil
+}
+
+// errTooManyPages reports a list that did not end within maxPaginationRequests pages,
+// instead of silently returning only part of it.
+func errTooManyPages(endpoint string) error {
+	return fmt.Errorf("список Taiga %s не закінчився після %d сторінок", endpoint, maxPaginationRequests)
+}
+
+// errPaginationLoop reports a list whose x-pagination-next points back at a page already
+// read, so the rest of the list cannot be reached.
+func errPaginationLoop(endpoint string) error {
+	return fmt.Errorf("список Taiga %s посилається на вже прочитану сторінку", endpoint)
+}
@@ -0,0 +1,157 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ResolvedRef identifies the item a project reference number points to.
+type ResolvedRef struct {
+	Kind        ItemKind
+	ProjectSlug string
+	ID          int64
+	ProjectID   int64
+	Ref         int64
+}
+
+// GetProject fetches project by id.
+func (c *Client) GetProject(ctx context.Context, id int64) (Project, error) {
+	var project Project
+	if id <= 0 {
+		return project, errors.New("некоректний id проєкту")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("projects/%d", id)})
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &project)
+	if err != nil {
+		return project, err
+	}
+
+	return project, nil
+}
+
+// GetProjectBySlug fetches project by its slug.
+func (c *Client) GetProjectBySlug(ctx context.Context, slug string) (Project, error) {
+	var project Project
+
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		return project, errors.New("потрібен slug проєкту")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "projects/by_slug"})
+
+	query := endpoint.Query()
+	query.Set("slug", slug)
+
+	endpoint.RawQuery = query.Encode()
+
+	err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &project)
+	if err != nil {
+		return project, err
+	}
+
+	return project, nil
+}
+
+// FindProject fetches a project by numeric id or by slug.
+func (c *Client) FindProject(ctx context.Context, idOrSlug string) (Project, error) {
+	idOrSlug = strings.TrimSpace(idOrSlug)
+
+	if id, err := strconv.ParseInt(idOrSlug, 10, 64); err == nil {
+		return c.GetProject(ctx, id)
+	}
+
+	return c.GetProjectBySlug(ctx, idOrSlug)
+}
+
+// ResolveRef maps a project reference number to the user story, task, issue or epic it belongs to.
+// The project is given by slug or numeric id; an id costs one extra request to learn the slug.
+func (c *Client) ResolveRef(ctx context.Context, project string, ref int64) (ResolvedRef, error) {
+	var resolved ResolvedRef
+
+	if ref <= 0 {
+		return resolved, errors.New("некоректний номер")
+	}
+
+	slug := strings.TrimSpace(project)
+	if slug == "" {
+		return resolved, errors.New("потрібен проєкт")
+	}
+
+	if id, err := strconv.ParseInt(slug, 10, 64); err == nil {
+		p, err := c.GetProject(ctx, id)
+		if err != nil {
+			return resolved, err
+		}
+
+		slug = p.Slug
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "resolver"})
+
+	query := endpoint.Query()
+	query.Set("project", slug)
+	query.Set("ref", strconv.FormatInt(ref, 10))
+
+	endpoint.RawQuery = query.Encode()
+
+	var out struct {
+		UserStory *int64 `json:"us"`
+		Task      *int64 `json:"task"`
+		Issue     *int64 `json:"issue"`
+		Epic      *int64 `json:"epic"`
+		Project   int64  `json:"project"`
+	}
+
+	if err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &out); err != nil {
+		return resolved, err
+	}
+
+	resolved = ResolvedRef{ProjectSlug: slug, ProjectID: out.Project, Ref: ref}
+
+	switch {
+	case out.UserStory != nil:
+		resolved.Kind, resolved.ID = KindUserStory, *out.UserStory
+	case out.Task != nil:
+		resolved.Kind, resolved.ID = KindTask, *out.Task
+	case out.Issue != nil:
+		resolved.Kind, resolved.ID = KindIssue, *out.Issue
+	case out.Epic != nil:
+		resolved.Kind, resolved.ID = KindEpic, *out.Epic
+	default:
+		return resolved, fmt.Errorf("не знайдено #%d у проєкті %s", ref, slug)
+	}
+
+	return resolved, nil
+}
+
+// ResolveItem resolves a project reference number and fetches the item it points to.
+func (c *Client) ResolveItem(ctx context.Context, project string, ref int64) (Item, error) {
+	resolved, err := c.ResolveRef(ctx, project, ref)
+	if err != nil {
+		return Item{}, err
+	}
+
+	return c.GetItem(ctx, resolved.Kind, resolved.ID)
+}
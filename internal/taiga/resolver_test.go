@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newResolverServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/projects/3":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 3, "slug": "team-board", "name": "Team"})
+		case "/api/v1/resolver":
+			if r.URL.Query().Get("project") != "team-board" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+
+			switch r.URL.Query().Get("ref") {
+			case "12":
+				_ = json.NewEncoder(w).Encode(map[string]any{"project": 3, "us": 120})
+			case "13":
+				_ = json.NewEncoder(w).Encode(map[string]any{"project": 3, "task": 130})
+			case "14":
+				_ = json.NewEncoder(w).Encode(map[string]any{"project": 3, "issue": 140})
+			case "15":
+				_ = json.NewEncoder(w).Encode(map[string]any{"project": 3, "epic": 150})
+			case "16":
+				_ = json.NewEncoder(w).Encode(map[string]any{"project": 3, "wikipage": 160})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		case "/api/v1/epics/150":
+			_ = json.NewEncoder(w).Encode(map[string]any{"id": 150, "ref": 15, "project": 3, "subject": "Billing"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_ResolveRef(t *testing.T) {
+	t.Parallel()
+
+	srv := newResolverServer(t)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	tests := []struct {
+		project string
+		kind    ItemKind
+		ref     int64
+		id      int64
+	}{
+		{project: "team-board", ref: 12, kind: KindUserStory, id: 120},
+		{project: "team-board", ref: 13, kind: KindTask, id: 130},
+		{project: "3", ref: 14, kind: KindIssue, id: 140},
+		{project: "3", ref: 15, kind: KindEpic, id: 150},
+	}
+
+	for _, tt := range tests {
+		got, err := c.ResolveRef(t.Context(), tt.project, tt.ref)
+		if err != nil {
+			t.Fatalf("ResolveRef(%s#%d): %v", tt.project, tt.ref, err)
+		}
+
+		want := ResolvedRef{Kind: tt.kind, ID: tt.id, ProjectID: 3, ProjectSlug: "team-board", Ref: tt.ref}
+		if got != want {
+			t.Fatalf("ResolveRef(%s#%d): got=%+v want=%+v", tt.project, tt.ref, got, want)
+		}
+	}
+
+	if _, err := c.ResolveRef(t.Context(), "team-board", 16); err == nil {
+		t.Fatalf("expected error for a ref that is not a work item")
+	}
+
+	if _, err := c.ResolveRef(t.Context(), "team-board", 99); err == nil {
+		t.Fatalf("expected error for unknown ref")
+	}
+
+	if _, err := c.ResolveRef(t.Context(), "", 12); err == nil {
+		t.Fatalf("expected error for empty project")
+	}
+}
+
+func TestClient_ResolveItem(t *testing.T) {
+	t.Parallel()
+
+	srv := newResolverServer(t)
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	got, err := c.ResolveItem(t.Context(), "team-board", 15)
+	if err != nil {
+		t.Fatalf("ResolveItem: %v", err)
+	}
+
+	if got.Kind != KindEpic || got.ID != 150 || got.Subject != "Billing" {
+		t.Fatalf("unexpected item: %+v", got)
+	}
+}
@@ -0,0 +1,278 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var fastRetryPolicy = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+// flakyServer fails the first failures requests with status, then answers with a user.
+func flakyServer(t *testing.T, failures int32, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+
+			w.WriteHeader(status)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": 1, "full_name_display": "Olena"})
+	}))
+
+	return srv, &calls
+}
+
+func TestClient_RetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		srv, calls := flakyServer(t, 2, status, "")
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		c.SetRetryPolicy(fastRetryPolicy)
+
+		user, err := c.GetMe(t.Context())
+		if err != nil {
+			t.Fatalf("status %d: GetMe: %v", status, err)
+		}
+
+		if user.ID != 1 || calls.Load() != 3 {
+			t.Fatalf("status %d: unexpected result: user=%+v calls=%d", status, user, calls.Load())
+		}
+
+		srv.Close()
+	}
+}
+
+func TestClient_RetryGivesUp(t *testing.T) {
+	t.Parallel()
+
+	t.Run("max_attempts", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 10, http.StatusServiceUnavailable, "")
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		c.SetRetryPolicy(fastRetryPolicy)
+
+		if _, err := c.GetMe(t.Context()); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if calls.Load() != 3 {
+			t.Fatalf("expected 3 attempts, got %d", calls.Load())
+		}
+	})
+
+	t.Run("not_transient", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 10, http.StatusInternalServerError, "")
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		c.SetRetryPolicy(fastRetryPolicy)
+
+		if _, err := c.GetMe(t.Context()); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if calls.Load() != 1 {
+			t.Fatalf("expected a single attempt, got %d", calls.Load())
+		}
+	})
+
+	t.Run("retry_after_too_long", func(t *testing.T) {
+		t.Parallel()
+
+		srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, "120")
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		c.SetRetryPolicy(fastRetryPolicy)
+
+		if _, err := c.GetMe(t.Context()); err == nil {
+			t.Fatalf("expected error")
+		}
+
+		if calls.Load() != 1 {
+			t.Fatalf("expected a single attempt, got %d", calls.Load())
+		}
+	})
+}
+
+func TestClient_RetryAfterHonoured(t *testing.T) {
+	t.Parallel()
+
+	srv, calls := flakyServer(t, 1, http.StatusTooManyRequests, "0")
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	if _, err := c.GetMe(t.Context()); err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestClient_RetryNonIdempotent(t *testing.T) {
+	t.Parallel()
+
+	req := TaskCreateRequest{ProjectID: 1, Subject: "Task"}
+
+	srv, calls := flakyServer(t, 1, http.StatusServiceUnavailable, "")
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	c.SetRetryPolicy(fastRetryPolicy)
+
+	if _, err := c.CreateTask(t.Context(), req); err == nil {
+		t.Fatalf("expected POST not to be retried by default")
+	}
+
+	if calls.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", calls.Load())
+	}
+
+	calls.Store(0)
+
+	policy := fastRetryPolicy
+	policy.RetryNonIdempotent = true
+	c.SetRetryPolicy(policy)
+
+	if _, err := c.CreateTask(t.Context(), req); err != nil {
+		t.Fatalf("CreateTask: %v", err)
+	}
+
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+}
+
+func TestClient_RetryRespectsContext(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := flakyServer(t, 10, http.StatusServiceUnavailable, "")
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	_, err = c.GetMe(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline error, got %v", err)
+	}
+
+	if time.Since(start) > 5*time.Second {
+		t.Fatalf("retry did not stop with the context")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		raw  string
+		want time.Duration
+		ok   bool
+	}{
+		{raw: "", ok: false},
+		{raw: "3", want: 3 * time.Second, ok: true},
+		{raw: "-1", ok: false},
+		{raw: "Fri, 16 Oct 2026 10:00:30 GMT", want: 30 * time.Second, ok: true},
+		{raw: "Fri, 16 Oct 2026 09:00:00 GMT", want: 0, ok: true},
+		{raw: "soon", ok: false},
+	}
+
+	for _, tc := range cases {
+		got, ok := parseRetryAfter(tc.raw, now)
+		if ok != tc.ok || got != tc.want {
+			t.Fatalf("parseRetryAfter(%q): got=%v,%v want=%v,%v", tc.raw, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	p := RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, upper := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 8: time.Second} {
+		for range 20 {
+			got := p.backoff(attempt)
+			if got < upper/2 || got > upper {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", attempt, got, upper/2, upper)
+			}
+		}
+	}
+}
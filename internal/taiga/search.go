@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SearchResult is a user story, task or issue matched by Search.
+type SearchResult struct {
+	Kind            ItemKind        `json:"-"`
+	Subject         string          `json:"subject"`
+	StatusExtraInfo StatusExtraInfo `json:"status_extra_info"`
+	ID              int64           `json:"id"`
+	Ref             int64           `json:"ref"`
+	StatusID        int64           `json:"status"`
+}
+
+// SearchResults groups Search matches by item kind.
+type SearchResults struct {
+	UserStories []SearchResult `json:"userstories"`
+	Tasks       []SearchResult `json:"tasks"`
+	Issues      []SearchResult `json:"issues"`
+}
+
+// Len returns the number of matches of every kind.
+func (r SearchResults) Len() int {
+	return len(r.UserStories) + len(r.Tasks) + len(r.Issues)
+}
+
+// Search runs Taiga's full-text search over user stories, tasks and issues of a project.
+// Taiga reports only status ids for matches; their names are looked up in the
+// project's cached statuses and left empty if those cannot be fetched.
+func (c *Client) Search(ctx context.Context, projectID int64, text string) (SearchResults, error) {
+	var results SearchResults
+
+	text = strings.TrimSpace(text)
+	if projectID <= 0 || text == "" {
+		return results, errors.New("потрібні проєкт і текст пошуку")
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: "search"})
+
+	query := endpoint.Query()
+	query.Set("project", strconv.FormatInt(projectID, 10))
+	query.Set("text", text)
+
+	endpoint.RawQuery = query.Encode()
+
+	if err := c.do(ctx, http.MethodGet, endpoint.String(), nil, &results); err != nil {
+		return results, err
+	}
+
+	c.fillSearchResults(ctx, KindUserStory, projectID, results.UserStories)
+	c.fillSearchResults(ctx, KindTask, projectID, results.Tasks)
+	c.fillSearchResults(ctx, KindIssue, projectID, results.Issues)
+
+	return results, nil
+}
+
+// fillSearchResults sets the kind and, where Taiga left it out, the status name of matches.
+func (c *Client) fillSearchResults(ctx context.Context, kind ItemKind, projectID int64, results []SearchResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	var names map[int64]string
+
+	for i := range results {
+		results[i].Kind = kind
+
+		if results[i].StatusExtraInfo.Name != "" || results[i].StatusID == 0 {
+			continue
+		}
+
+		if names == nil {
+			names = make(map[int64]string)
+
+			statuses, err := c.ListStatuses(ctx, kind, projectID)
+			if err == nil {
+				for _, status := range statuses {
+					names[status.ID] = status.Name
+				}
+			}
+		}
+
+		results[i].StatusExtraInfo.Name = names[results[i].StatusID]
+	}
+}
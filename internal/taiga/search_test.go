@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Search(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/api/v1/search":
+			if r.URL.Query().Get("project") != "3" || r.URL.Query().Get("text") != "login page" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"userstories": []map[string]any{{"id": 120, "ref": 12, "subject": "Login page", "status": 1}},
+				"tasks":       []map[string]any{{"id": 130, "ref": 13, "subject": "Style login page", "status": 7}},
+				"issues":      []map[string]any{},
+				"wikipages":   []map[string]any{{"id": 1, "slug": "login"}},
+				"count":       3,
+			})
+		case "/api/v1/userstory-statuses":
+			_ = json.NewEncoder(w).Encode([]Status{{ID: 1, Name: "New"}})
+		case "/api/v1/task-statuses":
+			_ = json.NewEncoder(w).Encode([]Status{{ID: 7, Name: "In progress"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(srv.URL+"/api/v1", "token")
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	got, err := c.Search(context.Background(), 3, " login page ")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if got.Len() != 2 {
+		t.Fatalf("unexpected matches: %+v", got)
+	}
+
+	us := got.UserStories[0]
+	if us.Kind != KindUserStory || us.Ref != 12 || us.StatusExtraInfo.Name != "New" {
+		t.Fatalf("unexpected user story: %+v", us)
+	}
+
+	task := got.Tasks[0]
+	if task.Kind != KindTask || task.ID != 130 || task.StatusExtraInfo.Name != "In progress" {
+		t.Fatalf("unexpected task: %+v", task)
+	}
+
+	if _, err := c.Search(context.Background(), 3, "  "); err == nil {
+		t.Fatalf("expected error for empty text")
+	}
+}
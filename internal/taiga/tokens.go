@@ -0,0 +1,254 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// refreshLeeway is how long before its expiry an auth token is refreshed proactively.
+const refreshLeeway = time.Minute
+
+// ErrRefreshRejected is returned when the refresh token has expired or Taiga refused it,
+// so the user has to link their account again.
+var ErrRefreshRejected = errors.New("Taiga не прийняла refresh токен")
+
+// TokenSource holds a user's auth and refresh tokens and refreshes them at most once at a time.
+// It is safe for concurrent use and may be shared by any number of clients.
+type TokenSource struct {
+	inflight     *refreshCall
+	onRefresh    func(authToken, refreshToken string)
+	now          func() time.Time
+	scheme       string
+	authToken    string
+	refreshToken string
+	mu           sync.Mutex
+	// closed is set by TokenSources.Forget: refreshes finishing later are not reported to onRefresh.
+	closed bool
+}
+
+// refreshCall is a refresh in progress that other callers wait for instead of starting their own.
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// refreshFunc exchanges a refresh token for a new auth token and, optionally, a new refresh token.
+type refreshFunc func(ctx context.Context, refreshToken string) (authToken, newRefreshToken string, err error)
+
+// NewTokenSource returns a token source. onRefresh, if set, is called with the new tokens
+// after every successful refresh, before any waiting request continues. Calls never overlap,
+// and none is made once the source was dropped by TokenSources.Forget.
+func NewTokenSource(authToken, refreshToken string, onRefresh func(authToken, refreshToken string)) *TokenSource {
+	return &TokenSource{
+		authToken:    authToken,
+		refreshToken: refreshToken,
+		onRefresh:    onRefresh,
+		now:          time.Now,
+		scheme:       "Bearer",
+	}
+}
+
+// NewApplicationTokenSource returns a source for a Taiga application token.
+// Application tokens do not expire and are never refreshed.
+func NewApplicationTokenSource(token string) *TokenSource {
+	return &TokenSource{
+		authToken: token,
+		now:       time.Now,
+		scheme:    "Application",
+	}
+}
+
+// Token returns the current auth token.
+func (s *TokenSource) Token() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.authToken
+}
+
+// canRefresh reports whether a refresh token is available.
+func (s *TokenSource) canRefresh() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return strings.TrimSpace(s.refreshToken) != ""
+}
+
+// validToken returns the auth token, first refreshing it if it expires within refreshLeeway.
+// A failed refresh is only reported once the current token has actually expired.
+func (s *TokenSource) validToken(ctx context.Context, exchange refreshFunc) (string, error) {
+	token := s.Token()
+
+	expiry, ok := TokenExpiry(token)
+	if !ok || s.now().Add(refreshLeeway).Before(expiry) || !s.canRefresh() {
+		return token, nil
+	}
+
+	if err := s.refresh(ctx, token, exchange); err != nil {
+		if s.now().Before(expiry) {
+			return token, nil
+		}
+
+		return "", err
+	}
+
+	return s.Token(), nil
+}
+
+// refresh replaces the stale auth token. If another caller already replaced it, or is
+// replacing it right now, no new refresh is started and that caller's result is reused.
+func (s *TokenSource) refresh(ctx context.Context, stale string, exchange refreshFunc) error {
+	s.mu.Lock()
+	if s.authToken != stale {
+		s.mu.Unlock()
+		return nil
+	}
+
+	if call := s.inflight; call != nil {
+		s.mu.Unlock()
+
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	refreshToken := strings.TrimSpace(s.refreshToken)
+	if refreshToken == "" {
+		s.mu.Unlock()
+		return errors.New("потрібен refresh токен")
+	}
+
+	if expiry, ok := TokenExpiry(refreshToken); ok && !s.now().Before(expiry) {
+		s.mu.Unlock()
+		return fmt.Errorf("%w: термін дії минув %s", ErrRefreshRejected, expiry.Format(time.DateTime))
+	}
+
+	call := &refreshCall{done: make(chan struct{})}
+	s.inflight = call
+	s.mu.Unlock()
+
+	authToken, newRefresh, err := exchange(ctx, refreshToken)
+
+	s.mu.Lock()
+	if err == nil {
+		s.authToken = authToken
+		if strings.TrimSpace(newRefresh) != "" {
+			s.refreshToken = newRefresh
+		}
+
+		authToken, newRefresh = s.authToken, s.refreshToken
+	}
+
+	notify := err == nil && s.onRefresh != nil && !s.closed
+	s.mu.Unlock()
+
+	// onRefresh usually persists the tokens, so it runs without holding the lock. The refresh
+	// stays in flight until it returns, which keeps calls from overlapping.
+	if notify {
+		s.onRefresh(authToken, newRefresh)
+	}
+
+	s.mu.Lock()
+	call.err = err
+	s.inflight = nil
+	s.mu.Unlock()
+
+	close(call.done)
+
+	return err
+}
+
+// close stops reporting refreshes to onRefresh.
+func (s *TokenSource) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+}
+
+// TokenExpiry returns the "exp" claim of a JWT. The signature is not verified;
+// ok is false when the token is not a JWT or carries no expiry.
+func TokenExpiry(token string) (time.Time, bool) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp *float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(*claims.Exp), 0), true
+}
+
+// TokenSources keeps one TokenSource per user, so every client of a user shares
+// its tokens and a refresh is never run twice with the same refresh token.
+type TokenSources struct {
+	sources map[int64]*TokenSource
+	mu      sync.Mutex
+}
+
+// NewTokenSources returns an empty registry.
+func NewTokenSources() *TokenSources {
+	return &TokenSources{sources: make(map[int64]*TokenSource)}
+}
+
+// Get returns the token source of a user, creating it with newSource on first use.
+// Later calls return the existing source until Forget is called.
+func (r *TokenSources) Get(userID int64, newSource func() *TokenSource) *TokenSource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if source, ok := r.sources[userID]; ok {
+		return source
+	}
+
+	source := newSource()
+	r.sources[userID] = source
+
+	return source
+}
+
+// Forget drops the token source of a user, e.g. after the user linked new tokens or unlinked.
+// A refresh still running on the dropped source no longer reports its tokens to onRefresh.
+func (r *TokenSources) Forget(userID int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if source, ok := r.sources[userID]; ok {
+		source.close()
+		delete(r.sources, userID)
+	}
+}
@@ -0,0 +1,281 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenSource_SingleFlightRefresh(t *testing.T) {
+	t.Parallel()
+
+	var refreshCalls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users/me":
+			if r.Header.Get("Authorization") != "Bearer new-auth" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(User{ID: 5})
+		case "/api/v1/auth/refresh":
+			refreshCalls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"auth_token": "new-auth", "refresh": "new-refresh"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	var saved atomic.Int32
+
+	sources := NewTokenSources()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			source := sources.Get(1, func() *TokenSource {
+				return NewTokenSource("old-auth", "old-refresh", func(string, string) { saved.Add(1) })
+			})
+
+			c, err := NewClientWithTokenSource(srv.URL+"/api/v1", source)
+			if err != nil {
+				t.Errorf("NewClientWithTokenSource: %v", err)
+				return
+			}
+
+			if _, err := c.GetMe(context.Background()); err != nil {
+				t.Errorf("GetMe: %v", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if got := refreshCalls.Load(); got != 1 {
+		t.Fatalf("unexpected refresh calls: %d", got)
+	}
+
+	if got := saved.Load(); got != 1 {
+		t.Fatalf("unexpected onRefresh calls: %d", got)
+	}
+
+	if got := sources.Get(1, nil).Token(); got != "new-auth" {
+		t.Fatalf("unexpected token: %q", got)
+	}
+
+	sources.Forget(1)
+
+	if got := sources.Get(1, func() *TokenSource { return NewTokenSource("relinked", "", nil) }).Token(); got != "relinked" {
+		t.Fatalf("unexpected token after Forget: %q", got)
+	}
+}
+
+func TestTokenSources_ForgetFencesRefresh(t *testing.T) {
+	t.Parallel()
+
+	var (
+		saved  atomic.Int32
+		source *TokenSource
+	)
+
+	sources := NewTokenSources()
+	source = sources.Get(1, func() *TokenSource {
+		return NewTokenSource("old-auth", "old-refresh", func(authToken, _ string) {
+			// The callback runs without the source's lock held.
+			if source.Token() != authToken {
+				t.Errorf("unexpected token in callback: %q", source.Token())
+			}
+
+			saved.Add(1)
+		})
+	})
+
+	exchange := func(context.Context, string) (string, string, error) {
+		return "new-auth", "new-refresh", nil
+	}
+
+	if err := source.refresh(t.Context(), "old-auth", exchange); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	if got := saved.Load(); got != 1 {
+		t.Fatalf("unexpected onRefresh calls: %d", got)
+	}
+
+	// A refresh still running when the user relinks must not report its tokens.
+	started, release := make(chan struct{}), make(chan struct{})
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- source.refresh(t.Context(), "new-auth", func(context.Context, string) (string, string, error) {
+			close(started)
+			<-release
+
+			return "late-auth", "late-refresh", nil
+		})
+	}()
+
+	<-started
+	sources.Forget(1)
+	close(release)
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+
+	if got := saved.Load(); got != 1 {
+		t.Fatalf("refresh after Forget reached onRefresh: %d calls", got)
+	}
+}
+
+// testJWT builds an unsigned JWT expiring at exp.
+func testJWT(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, `{"exp":%d,"user_id":5}`, exp.Unix()))
+
+	return "eyJhbGciOiJIUzI1NiJ9." + payload + ".sig"
+}
+
+func TestTokenExpiry(t *testing.T) {
+	t.Parallel()
+
+	exp := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	got, ok := TokenExpiry(testJWT(exp))
+	if !ok || !got.Equal(exp) {
+		t.Fatalf("unexpected expiry: %v %v", got, ok)
+	}
+
+	for _, token := range []string{"", "opaque-token", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".c"} {
+		if _, ok := TokenExpiry(token); ok {
+			t.Fatalf("expected no expiry for %q", token)
+		}
+	}
+}
+
+func TestTokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	expiring := testJWT(now.Add(30 * time.Second))
+
+	var refreshCalls int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/users/me":
+			if r.Header.Get("Authorization") != "Bearer new-auth" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(User{ID: 5})
+		case "/api/v1/auth/refresh":
+			refreshCalls++
+
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]string{"auth_token": "new-auth"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	source := NewTokenSource(expiring, testJWT(now.Add(24*time.Hour)), nil)
+	source.now = func() time.Time { return now }
+
+	c, err := NewClientWithTokenSource(srv.URL+"/api/v1", source)
+	if err != nil {
+		t.Fatalf("NewClientWithTokenSource: %v", err)
+	}
+
+	if _, err := c.GetMe(context.Background()); err != nil {
+		t.Fatalf("GetMe: %v", err)
+	}
+
+	if refreshCalls != 1 {
+		t.Fatalf("unexpected refresh calls: %d", refreshCalls)
+	}
+}
+
+func TestTokenSource_ExpiredRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/auth/refresh" {
+			t.Errorf("refresh must not be attempted with an expired refresh token")
+		}
+
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	source := NewTokenSource(testJWT(now.Add(-time.Minute)), testJWT(now.Add(-time.Hour)), nil)
+	source.now = func() time.Time { return now }
+
+	c, err := NewClientWithTokenSource(srv.URL+"/api/v1", source)
+	if err != nil {
+		t.Fatalf("NewClientWithTokenSource: %v", err)
+	}
+
+	if _, err := c.GetMe(context.Background()); !errors.Is(err, ErrRefreshRejected) {
+		t.Fatalf("expected ErrRefreshRejected, got %v", err)
+	}
+}
+
+func TestClient_RefreshRejected(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"detail":"Token is invalid or expired","code":"token_not_valid"}`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClientWithTokens(srv.URL+"/api/v1", "old-auth", "old-refresh", nil)
+	if err != nil {
+		t.Fatalf("NewClientWithTokens: %v", err)
+	}
+
+	_, err = c.GetMe(context.Background())
+	if !errors.Is(err, ErrRefreshRejected) || !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected rejected refresh, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ErrVersionConflict is matched by errors.Is when Taiga rejects an update carrying a stale version.
+var ErrVersionConflict = errors.New("версія обʼєкта Taiga застаріла")
+
+// ConflictError reports an update rejected because the item changed since Version was read.
+// Err is the underlying *APIError, so errors.Is also matches ErrValidation.
+type ConflictError struct {
+	Err     error
+	Kind    ItemKind
+	ID      int64
+	Version int64
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("конфлікт версій %s %d (версія %d): %v", e.Kind, e.ID, e.Version, e.Err)
+}
+
+// Is reports ErrVersionConflict.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrVersionConflict
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// UpdateOptions tunes optimistic concurrency handling of update calls.
+type UpdateOptions struct {
+	// RetryOnConflict re-fetches the current version and resends the update once after a conflict.
+	RetryOnConflict bool
+}
+
+// UserStoryUpdateRequest represents a partial user story update.
+// A zero Version makes the client read the current version first.
+type UserStoryUpdateRequest struct {
+	StatusID    *int64   `json:"status,omitempty"`
+	Assigned    *int64   `json:"assigned_to,omitempty"`
+	MilestoneID *int64   `json:"milestone,omitempty"`
+	Subject     *string  `json:"subject,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Comment     string   `json:"comment,omitempty"`
+	Version     int64    `json:"version"`
+}
+
+// TaskUpdateRequest represents a partial task update.
+// A zero Version makes the client read the current version first.
+type TaskUpdateRequest struct {
+	StatusID    *int64   `json:"status,omitempty"`
+	Assigned    *int64   `json:"assigned_to,omitempty"`
+	UserStory   *int64   `json:"user_story,omitempty"`
+	Subject     *string  `json:"subject,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Comment     string   `json:"comment,omitempty"`
+	Version     int64    `json:"version"`
+}
+
+// IssueUpdateRequest represents a partial issue update.
+// A zero Version makes the client read the current version first.
+type IssueUpdateRequest struct {
+	StatusID    *int64   `json:"status,omitempty"`
+	Assigned    *int64   `json:"assigned_to,omitempty"`
+	SeverityID  *int64   `json:"severity,omitempty"`
+	PriorityID  *int64   `json:"priority,omitempty"`
+	TypeID      *int64   `json:"type,omitempty"`
+	Subject     *string  `json:"subject,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Comment     string   `json:"comment,omitempty"`
+	Version     int64    `json:"version"`
+}
+
+// EpicUpdateRequest represents a partial epic update.
+// A zero Version makes the client read the current version first.
+type EpicUpdateRequest struct {
+	StatusID    *int64   `json:"status,omitempty"`
+	Assigned    *int64   `json:"assigned_to,omitempty"`
+	Subject     *string  `json:"subject,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Color       *string  `json:"color,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Comment     string   `json:"comment,omitempty"`
+	Version     int64    `json:"version"`
+}
+
+// UpdateUserStory patches a user story.
+func (c *Client) UpdateUserStory(ctx context.Context, id int64, req UserStoryUpdateRequest, opts UpdateOptions) (UserStory, error) {
+	return updateItem[UserStory](ctx, c, KindUserStory, id, req.Version, opts, func(version int64) any {
+		req.Version = version
+		return req
+	})
+}
+
+// UpdateTask patches a task.
+func (c *Client) UpdateTask(ctx context.Context, id int64, req TaskUpdateRequest, opts UpdateOptions) (Task, error) {
+	return updateItem[Task](ctx, c, KindTask, id, req.Version, opts, func(version int64) any {
+		req.Version = version
+		return req
+	})
+}
+
+// UpdateIssue patches an issue.
+func (c *Client) UpdateIssue(ctx context.Context, id int64, req IssueUpdateRequest, opts UpdateOptions) (Issue, error) {
+	return updateItem[Issue](ctx, c, KindIssue, id, req.Version, opts, func(version int64) any {
+		req.Version = version
+		return req
+	})
+}
+
+// UpdateEpic patches an epic.
+func (c *Client) UpdateEpic(ctx context.Context, id int64, req EpicUpdateRequest, opts UpdateOptions) (Epic, error) {
+	return updateItem[Epic](ctx, c, KindEpic, id, req.Version, opts, func(version int64) any {
+		req.Version = version
+		return req
+	})
+}
+
+// updateItem sends a versioned PATCH built by payload, optionally retrying once with a fresh version.
+func updateItem[T any](ctx context.Context, c *Client, kind ItemKind, id, version int64, opts UpdateOptions, payload func(version int64) any) (T, error) {
+	var out T
+
+	collection, err := kind.collection()
+	if err != nil {
+		return out, err
+	}
+
+	if id <= 0 {
+		return out, errors.New("некоректний id обʼєкта")
+	}
+
+	if version <= 0 {
+		current, err := c.GetItem(ctx, kind, id)
+		if err != nil {
+			return out, err
+		}
+
+		version = current.Version
+	}
+
+	endpoint := c.baseURL.ResolveReference(&url.URL{Path: fmt.Sprintf("%s/%d", collection, id)}).String()
+
+	err = c.patchVersioned(ctx, kind, id, version, endpoint, payload(version), &out)
+	if err == nil || !opts.RetryOnConflict || !errors.Is(err, ErrVersionConflict) {
+		return out, err
+	}
+
+	current, err := c.GetItem(ctx, kind, id)
+	if err != nil {
+		return out, err
+	}
+
+	err = c.patchVersioned(ctx, kind, id, current.Version, endpoint, payload(current.Version), &out)
+
+	return out, err
+}
+
+func (c *Client) patchVersioned(ctx context.Context, kind ItemKind, id, version int64, endpoint string, payload, out any) error {
+	err := c.do(ctx, http.MethodPatch, endpoint, payload, out)
+	if err == nil {
+		return nil
+	}
+
+	if isVersionConflict(err) {
+		return &ConflictError{Kind: kind, ID: id, Version: version, Err: err}
+	}
+
+	return err
+}
+
+// isVersionConflict recognises Taiga's 400 response with a "version" field error.
+func isVersionConflict(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	_, ok := apiErr.FieldErrors["version"]
+
+	return ok
+}
@@ -0,0 +1,158 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taiga
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// versionedServer serves one user story and rejects PATCH requests carrying a stale version.
+type versionedServer struct {
+	status  string
+	version int64
+	patches int
+	mu      sync.Mutex
+}
+
+func (s *versionedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.URL.Path != "/api/v1/userstories/5" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodPatch {
+		s.patches++
+
+		var req struct {
+			Status  *int64 `json:"status"`
+			Version int64  `json:"version"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if req.Version != s.version {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"version": "The version doesn't match with the current one"}`))
+
+			return
+		}
+
+		if req.Status != nil {
+			s.status = "Done"
+		}
+
+		s.version++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"id":                5,
+		"ref":               1,
+		"version":           s.version,
+		"status_extra_info": map[string]any{"name": s.status},
+	})
+}
+
+func TestClient_UpdateUserStory(t *testing.T) {
+	t.Parallel()
+
+	statusID := int64(3)
+
+	t.Run("reads_version_when_missing", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(&versionedServer{status: "New", version: 7})
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		got, err := c.UpdateUserStory(t.Context(), 5, UserStoryUpdateRequest{StatusID: &statusID}, UpdateOptions{})
+		if err != nil {
+			t.Fatalf("UpdateUserStory: %v", err)
+		}
+
+		if got.Version != 8 || got.StatusExtraInfo.Name != "Done" {
+			t.Fatalf("unexpected story: %+v", got)
+		}
+	})
+
+	t.Run("stale_version_conflict", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(&versionedServer{status: "New", version: 7})
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		_, err = c.UpdateUserStory(t.Context(), 5, UserStoryUpdateRequest{StatusID: &statusID, Version: 6}, UpdateOptions{})
+		if !errors.Is(err, ErrVersionConflict) {
+			t.Fatalf("expected version conflict, got %v", err)
+		}
+
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected ConflictError, got %T", err)
+		}
+
+		if conflict.Kind != KindUserStory || conflict.ID != 5 || conflict.Version != 6 {
+			t.Fatalf("unexpected conflict: %+v", conflict)
+		}
+	})
+
+	t.Run("retry_on_conflict", func(t *testing.T) {
+		t.Parallel()
+
+		backend := &versionedServer{status: "New", version: 7}
+
+		srv := httptest.NewServer(backend)
+		defer srv.Close()
+
+		c, err := NewClient(srv.URL+"/api/v1", "token")
+		if err != nil {
+			t.Fatalf("NewClient: %v", err)
+		}
+
+		got, err := c.UpdateUserStory(t.Context(), 5, UserStoryUpdateRequest{StatusID: &statusID, Version: 6}, UpdateOptions{RetryOnConflict: true})
+		if err != nil {
+			t.Fatalf("UpdateUserStory: %v", err)
+		}
+
+		if got.Version != 8 {
+			t.Fatalf("unexpected version: %d", got.Version)
+		}
+
+		if backend.patches != 2 {
+			t.Fatalf("unexpected patches: %d", backend.patches)
+		}
+	})
+}
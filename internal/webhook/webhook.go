@@ -0,0 +1,238 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook receives Taiga project webhooks.
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA1 of the request body keyed with the project secret.
+	SignatureHeader = "X-TAIGA-WEBHOOK-SIGNATURE"
+
+	// Pattern is the route Taiga webhooks are expected on, one URL per project.
+	Pattern = "POST /webhooks/taiga/{project_id}"
+
+	maxBodySize = 1 << 20
+
+	// queueSize is how many verified events may wait for the callback before deliveries are refused.
+	queueSize = 256
+)
+
+// Event actions sent by Taiga.
+const (
+	ActionCreate = "create"
+	ActionChange = "change"
+	ActionDelete = "delete"
+	ActionTest   = "test"
+)
+
+// Event is a Taiga webhook payload subset used by the bot.
+type Event struct {
+	Change *Change   `json:"change"`
+	Action string    `json:"action"`
+	Type   string    `json:"type"`
+	Date   string    `json:"date"`
+	By     User      `json:"by"`
+	Data   EventData `json:"data"`
+}
+
+// EventData describes the changed object.
+type EventData struct {
+	AssignedTo *User   `json:"assigned_to"`
+	Status     *Status `json:"status"`
+	Subject    string  `json:"subject"`
+	Project    Project `json:"project"`
+	ID         int64   `json:"id"`
+	Ref        int64   `json:"ref"`
+	Version    int64   `json:"version"`
+}
+
+// Change holds the comment and field differences of a change event.
+type Change struct {
+	Diff    map[string]DiffValue `json:"diff"`
+	Comment string               `json:"comment"`
+}
+
+// DiffValue is the old and new value of one changed field.
+type DiffValue struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// User is the actor or assignee of an event.
+type User struct {
+	FullName string `json:"full_name"`
+	Username string `json:"username"`
+	ID       int64  `json:"id"`
+}
+
+// Status is the status of the changed object.
+type Status struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+}
+
+// Project is the project the changed object belongs to.
+type Project struct {
+	Name      string `json:"name"`
+	Permalink string `json:"permalink"`
+	ID        int64  `json:"id"`
+}
+
+// Sign returns the signature Taiga sends for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature matches body for secret.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return false
+	}
+
+	want, _ := hex.DecodeString(Sign(secret, body))
+
+	return hmac.Equal(got, want)
+}
+
+// Handler accepts signed webhooks of configured projects and hands events to a callback.
+// Deliveries are acknowledged as soon as they are verified; Run passes the queued events
+// to the callback one at a time, in the order they arrived.
+// It also remembers when each project last delivered, so polling can step back while webhooks work.
+type Handler struct {
+	secrets  map[int64]string
+	handle   func(ctx context.Context, projectID int64, event Event)
+	lastSeen map[int64]time.Time
+	now      func() time.Time
+	queue    chan delivery
+	window   time.Duration
+	mu       sync.Mutex
+}
+
+// delivery is a verified event waiting for the callback.
+type delivery struct {
+	event     Event
+	projectID int64
+}
+
+// NewHandler returns a handler for projects with a secret. A project counts as served by webhooks
+// for window after its last verified delivery.
+func NewHandler(secrets map[int64]string, window time.Duration, handle func(ctx context.Context, projectID int64, event Event)) *Handler {
+	return &Handler{
+		secrets:  secrets,
+		handle:   handle,
+		lastSeen: make(map[int64]time.Time),
+		now:      time.Now,
+		queue:    make(chan delivery, queueSize),
+		window:   window,
+	}
+}
+
+// Run hands queued events to the callback until ctx is done.
+func (h *Handler) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-h.queue:
+			if h.handle != nil {
+				h.handle(ctx, d.projectID, d.event)
+			}
+		}
+	}
+}
+
+// Register adds the webhook route to mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.Handle(Pattern, h)
+}
+
+// Active reports whether a project delivered a verified webhook recently enough to skip polling it.
+func (h *Handler) Active(projectID int64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	seen, ok := h.lastSeen[projectID]
+
+	return ok && h.now().Sub(seen) < h.window
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	projectID, err := strconv.ParseInt(r.PathValue("project_id"), 10, 64)
+	if err != nil || projectID <= 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	secret, ok := h.secrets[projectID]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !VerifySignature(secret, body, r.Header.Get(SignatureHeader)) {
+		log.Printf("webhook: invalid signature for project %d", projectID)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+
+		return
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if event.Action != ActionTest {
+		select {
+		case h.queue <- delivery{projectID: projectID, event: event}:
+		default:
+			log.Printf("webhook: queue full, dropping event for project %d", projectID)
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+
+			return
+		}
+	}
+
+	h.mu.Lock()
+	h.lastSeen[projectID] = h.now()
+	h.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2026 Sumicare
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const samplePayload = `{
+	"action": "change",
+	"type": "userstory",
+	"by": {"id": 4, "full_name": "Olena", "username": "olena"},
+	"date": "2026-10-16T10:00:00.000Z",
+	"data": {
+		"id": 120, "ref": 12, "subject": "Login", "version": 5,
+		"project": {"id": 3, "name": "Team", "permalink": "https://tree.taiga.io/project/team-board"},
+		"status": {"id": 2, "name": "In progress"},
+		"assigned_to": {"id": 7, "full_name": "Taras"}
+	},
+	"change": {"comment": "", "diff": {"status": {"from": "New", "to": "In progress"}}}
+}`
+
+func TestVerifySignature(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(samplePayload)
+	signature := Sign("s3cret", body)
+
+	if !VerifySignature("s3cret", body, signature) {
+		t.Fatalf("expected valid signature")
+	}
+
+	if VerifySignature("other", body, signature) {
+		t.Fatalf("expected signature mismatch for another secret")
+	}
+
+	if VerifySignature("s3cret", body, "not-hex") {
+		t.Fatalf("expected malformed signature to be rejected")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	type received struct {
+		event     Event
+		projectID int64
+	}
+
+	got := make(chan received, 1)
+
+	h := NewHandler(map[int64]string{3: "s3cret"}, time.Hour, func(_ context.Context, projectID int64, event Event) {
+		got <- received{projectID: projectID, event: event}
+	})
+
+	now := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	mux := http.NewServeMux()
+	h.Register(mux)
+
+	send := func(path, body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(SignatureHeader, signature)
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	if code := send("/webhooks/taiga/4", samplePayload, Sign("s3cret", []byte(samplePayload))); code != http.StatusNotFound {
+		t.Fatalf("unknown project: got status %d", code)
+	}
+
+	if code := send("/webhooks/taiga/3", samplePayload, Sign("wrong", []byte(samplePayload))); code != http.StatusUnauthorized {
+		t.Fatalf("bad signature: got status %d", code)
+	}
+
+	if h.Active(3) {
+		t.Fatalf("project must not be active before a verified delivery")
+	}
+
+	testPayload := `{"action": "test", "type": "test", "data": {}}`
+	if code := send("/webhooks/taiga/3", testPayload, Sign("s3cret", []byte(testPayload))); code != http.StatusNoContent {
+		t.Fatalf("test event: got status %d", code)
+	}
+
+	if len(h.queue) != 0 {
+		t.Fatalf("test events must not be dispatched")
+	}
+
+	if !h.Active(3) {
+		t.Fatalf("project must be active after a verified delivery")
+	}
+
+	// The delivery is acknowledged before the callback runs.
+	if code := send("/webhooks/taiga/3", samplePayload, Sign("s3cret", []byte(samplePayload))); code != http.StatusNoContent {
+		t.Fatalf("change event: got status %d", code)
+	}
+
+	go h.Run(t.Context())
+
+	var first received
+
+	select {
+	case first = <-got:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("event was not dispatched")
+	}
+
+	if first.projectID != 3 {
+		t.Fatalf("unexpected dispatch: %+v", first)
+	}
+
+	ev := first.event
+	if ev.Action != ActionChange || ev.Type != "userstory" || ev.Data.Ref != 12 || ev.Data.Version != 5 || ev.Data.Status.Name != "In progress" || ev.Data.AssignedTo.ID != 7 {
+		t.Fatalf("unexpected event: %+v", ev)
+	}
+
+	if diff, ok := ev.Change.Diff["status"]; !ok || diff.From != "New" || diff.To != "In progress" {
+		t.Fatalf("unexpected diff: %+v", ev.Change)
+	}
+
+	now = now.Add(2 * time.Hour)
+
+	if h.Active(3) {
+		t.Fatalf("project must fall back to polling after the window")
+	}
+}